@@ -0,0 +1,256 @@
+// origami-gen 命令行工具
+//
+// 用法:
+//
+//	origami-gen generate [flags] <import-path>...  为包生成绑定
+//	origami-gen list     [flags] <import-path>...  列出将被绑定的符号
+//	origami-gen clean    [flags]                   删除清单记录的生成文件
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/php-any/generator/scr"
+)
+
+// command 子命令定义
+type command struct {
+	name  string
+	usage string
+	// 注册子命令专有参数，可为空
+	flags func(fs *flag.FlagSet)
+	run   func(config *scr.Config, args []string) error
+}
+
+// errUsage 参数解析失败（flag 包已打印原因）
+var errUsage = errors.New("参数错误")
+
+var commands = []command{
+	{name: "generate", usage: "为包生成绑定", flags: generateFlags, run: runGenerate},
+	{name: "list", usage: "列出将被绑定的符号", run: runList},
+	{name: "clean", usage: "删除清单记录的生成文件", run: runClean},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		config, args, err := parseFlags(c, os.Args[2:])
+		if err != nil {
+			switch {
+			case errors.Is(err, flag.ErrHelp):
+				os.Exit(0)
+			case errors.Is(err, errUsage):
+				os.Exit(2)
+			}
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := c.run(config, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if name != "-h" && name != "--help" && name != "help" {
+		fmt.Fprintf(os.Stderr, "未知子命令: %s\n\n", name)
+	}
+	printUsage()
+	os.Exit(2)
+}
+
+// printUsage 打印总体用法
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "用法: origami-gen <command> [flags] [import-path...]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "命令:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.usage)
+	}
+}
+
+// parseFlags 解析子命令参数，返回配置与剩余的位置参数
+// 指定 -config 时先加载配置文件，再用命令行显式给出的参数覆盖
+func parseFlags(c command, args []string) (*scr.Config, []string, error) {
+	flagConfig := &scr.Config{}
+	var blacklist stringList
+	var configPath string
+
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.StringVar(&configPath, "config", "", "配置文件路径（.yaml/.yml/.json/.toml）")
+	fs.StringVar(&flagConfig.OutputRoot, "output", "origami", "输出根目录")
+	fs.StringVar(&flagConfig.NamePrefix, "prefix", "", "GetName 拼接前缀")
+	fs.IntVar(&flagConfig.MaxDepth, "max-depth", 1000, "最大递归生成层次（<=0 表示不限制）")
+	fs.IntVar(&flagConfig.Workers, "workers", 0, "并行生成的 worker 数（<=0 表示使用 GOMAXPROCS）")
+	fs.Var(&blacklist, "blacklist", "只生成 data.AnyValue 的包路径，可重复或以逗号分隔")
+	fs.BoolVar(&flagConfig.StrictNumericStrings, "strict-numeric", false, "生成的绑定在加载时开启数值字符串严格模式，字符串不再自动转换为数值参数")
+	if c.flags != nil {
+		c.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "用法: origami-gen %s [flags] [import-path...]\n\n", c.name)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errUsage, err)
+	}
+	flagConfig.Blacklist.Packages = blacklist
+
+	if configPath == "" {
+		return flagConfig, fs.Args(), nil
+	}
+
+	config, err := scr.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "output":
+			config.OutputRoot = flagConfig.OutputRoot
+		case "prefix":
+			config.NamePrefix = flagConfig.NamePrefix
+		case "max-depth":
+			config.MaxDepth = flagConfig.MaxDepth
+		case "workers":
+			config.Workers = flagConfig.Workers
+		case "blacklist":
+			config.Blacklist.Packages = flagConfig.Blacklist.Packages
+		case "strict-numeric":
+			config.StrictNumericStrings = flagConfig.StrictNumericStrings
+		}
+	})
+	return config, fs.Args(), nil
+}
+
+// generate 子命令专有参数
+var (
+	dryRun bool
+	check  bool
+)
+
+// errStale --check 发现生成结果已过期
+var errStale = errors.New("生成的绑定已过期，请重新生成")
+
+// generateFlags 注册 generate 子命令专有参数
+func generateFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&dryRun, "dry-run", false, "只打印将生成的文件路径与大小，不写入磁盘")
+	fs.BoolVar(&check, "check", false, "在内存中重新生成并与磁盘对比，存在差异时以非零状态退出")
+}
+
+// runGenerate 为每个导入路径生成绑定
+func runGenerate(config *scr.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("缺少导入路径")
+	}
+	// 生成前校验，尽早报告配置问题
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if dryRun && check {
+		return errors.New("-dry-run 与 -check 不能同时使用")
+	}
+	var dry *scr.DryRunFS
+	var mem *scr.MemoryFS
+	switch {
+	case dryRun:
+		dry = scr.NewDryRunFS()
+		config.Output = dry
+	case check:
+		mem = scr.NewMemoryFS()
+		config.Output = mem
+	}
+	// 全部包在一次驱动程序运行中生成，共享的输出子包写出完整的 load.go
+	report, err := scr.GenerateFromPackages(args, config)
+	if err != nil {
+		return err
+	}
+	// -check 只对比，不更新清单
+	if mem == nil {
+		removed, err := scr.SyncManifest(config, report)
+		if err != nil {
+			return err
+		}
+		if dry == nil {
+			for _, p := range removed {
+				fmt.Printf("已删除孤立文件 %s\n", p)
+			}
+		}
+	}
+	if dry != nil {
+		dry.Summary(os.Stdout)
+	}
+	// 无法生成的符号不中断流程，最后统一输出
+	if report.HasDiagnostics() {
+		fmt.Fprintln(os.Stderr, report)
+	}
+	if mem != nil {
+		result, err := scr.CheckOutput(config, mem, report)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		if result.HasDifferences() {
+			return errStale
+		}
+	}
+	return nil
+}
+
+// runList 打印每个包中将被绑定的符号
+func runList(config *scr.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("缺少导入路径")
+	}
+	infos, err := scr.LoadPackages(args)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		fmt.Printf("%s (%s)\n", info.Path, info.Name)
+		for _, sym := range info.Symbols {
+			fmt.Printf("\t%-10s %s\n", sym.Kind, sym.Name)
+		}
+	}
+	return nil
+}
+
+// runClean 删除清单记录的生成文件，不带生成标记的文件保留
+func runClean(config *scr.Config, args []string) error {
+	removed, kept, err := scr.Clean(config)
+	for _, p := range removed {
+		fmt.Printf("已删除 %s\n", p)
+	}
+	for _, p := range kept {
+		fmt.Fprintf(os.Stderr, "保留不带生成标记的文件 %s\n", p)
+	}
+	return err
+}
+
+// stringList 可重复、可逗号分隔的字符串参数
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
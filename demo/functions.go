@@ -2,6 +2,7 @@ package demo
 
 import (
 	"context"
+	"strconv"
 	"time"
 )
 
@@ -91,6 +92,115 @@ func CreateEvent(eventType string, data map[string]any, tags []string) *Event {
 	}
 }
 
+// CountActiveUsers 统计函数 - 测试具名多返回值
+func CountActiveUsers(users []*User) (active int, total int) {
+	for _, user := range users {
+		if user.IsActive {
+			active++
+		}
+	}
+	return active, len(users)
+}
+
+// StreamEvents 事件流函数 - 测试只读 channel 返回值
+func StreamEvents(events []*Event) <-chan *Event {
+	ch := make(chan *Event, len(events))
+	for _, event := range events {
+		ch <- event
+	}
+	close(ch)
+	return ch
+}
+
+// NewEventQueue 事件队列函数 - 测试双向 channel 返回值
+func NewEventQueue(size int) chan *Event {
+	return make(chan *Event, size)
+}
+
+// CollectEventTypes 事件收集函数 - 测试只读 channel 参数
+func CollectEventTypes(events <-chan *Event) []string {
+	var types []string
+	for event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// MergeMetadata 合并元数据函数 - 测试 map 参数与返回值
+func MergeMetadata(base map[string]string, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
+
+// PartitionUsers 用户分组函数 - 测试非字符串键的 map 参数与返回值
+func PartitionUsers(users map[int64]*User) map[bool][]string {
+	groups := make(map[bool][]string)
+	for _, user := range users {
+		groups[user.IsActive] = append(groups[user.IsActive], user.Name)
+	}
+	return groups
+}
+
+// IndexUsers 用户索引函数 - 测试非字符串键的 map 返回值保留键
+func IndexUsers(users []*User) map[int64]string {
+	index := make(map[int64]string, len(users))
+	for _, user := range users {
+		index[user.ID] = user.Name
+	}
+	return index
+}
+
+// Checksum 校验和函数 - 测试超出 int 范围的 uint64 返回值
+func Checksum(s string) uint64 {
+	sum := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		sum ^= uint64(s[i])
+		sum *= 1099511628211
+	}
+	return sum
+}
+
+// DescribeItem 描述函数 - 测试接口参数只接受实现了该接口的类
+func DescribeItem(item Describer) string {
+	return item.Describe()
+}
+
+// FlattenTags 标签展开函数 - 测试嵌套切片参数
+func FlattenTags(groups [][]string) []string {
+	var tags []string
+	for _, group := range groups {
+		tags = append(tags, group...)
+	}
+	return tags
+}
+
+// TotalTimeout 超时累加函数 - 测试具名类型切片参数
+func TotalTimeout(timeouts []time.Duration) time.Duration {
+	var total time.Duration
+	for _, timeout := range timeouts {
+		total += timeout
+	}
+	return total
+}
+
+// ParseResult 解析结果 - 与 Parse 的多返回值结果类同名
+type ParseResult struct {
+	Value int
+	Valid bool
+}
+
+// Parse 解析函数 - 测试多返回值结果类与包内同名类型的冲突
+func Parse(s string) (value int, ok bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
 // generateID 内部函数 - 测试私有函数（不应该被生成）
 func generateID() string {
 	return "event_" + time.Now().Format("20060102150405")
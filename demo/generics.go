@@ -0,0 +1,40 @@
+package demo
+
+// Cache 泛型缓存 - 测试泛型类型实例化（需在配置 instantiations 中列出，如 Cache[string,*User]）
+type Cache[K comparable, V any] struct {
+	Name  string
+	items map[K]V
+}
+
+// NewCache 构造泛型缓存
+func NewCache[K comparable, V any](name string) *Cache[K, V] {
+	return &Cache[K, V]{Name: name, items: make(map[K]V)}
+}
+
+// Set 写入缓存
+func (c *Cache[K, V]) Set(key K, value V) {
+	if c.items == nil {
+		c.items = make(map[K]V)
+	}
+	c.items[key] = value
+}
+
+// Get 读取缓存
+func (c *Cache[K, V]) Get(key K) (value V, found bool) {
+	value, found = c.items[key]
+	return
+}
+
+// Len 缓存条目数
+func (c *Cache[K, V]) Len() int {
+	return len(c.items)
+}
+
+// Map 泛型函数 - 测试泛型函数实例化（如 Map[int,string]）
+func Map[T, U any](items []T, fn func(T) U) []U {
+	out := make([]U, 0, len(items))
+	for _, item := range items {
+		out = append(out, fn(item))
+	}
+	return out
+}
@@ -158,3 +158,8 @@ func (e *Event) GetData(key string) (any, bool) {
 func (e *Event) SetData(key string, value any) {
 	e.Data[key] = value
 }
+
+// Describe 返回管理员描述
+func (a *AdminUser) Describe() string {
+	return a.Name + " (admin)"
+}
@@ -101,3 +101,14 @@ type privateStruct struct {
 type PrivateInterface interface {
 	GetValue() string
 }
+
+// AdminUser 管理员 - 测试嵌入结构体（继承与属性提升）
+type AdminUser struct {
+	User
+	Permissions []string `json:"permissions"`
+}
+
+// Describer 描述接口 - 测试接口实现匹配
+type Describer interface {
+	Describe() string
+}
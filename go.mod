@@ -5,13 +5,16 @@ go 1.24.0
 toolchain go1.24.6
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/php-any/origami v0.0.11-0.20250912083343-29c71fdaa427
 	github.com/redis/go-redis/v9 v9.12.1
+	golang.org/x/mod v0.33.0
+	golang.org/x/tools v0.42.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
-	github.com/go-sql-driver/mysql v1.9.3 // indirect
+	golang.org/x/sync v0.19.0 // indirect
 )
@@ -16,7 +16,9 @@ var config = scr.Config{
 	Blacklist: scr.BlacklistConfig{
 		Packages: []string{"time"},
 	},
-	PackageMappings: map[string]string{},
+	PackageMappings: map[string]string{
+		"github.com/redis/go-redis/v9": "redis",
+	},
 }
 
 var genList = []any{
@@ -28,12 +30,32 @@ var genList = []any{
 	redis.NewClient,
 }
 
+// 按导入路径整包生成，符号由源码分析自动发现
+var genPackages = []string{
+	//"github.com/redis/go-redis/v9",
+}
+
 func main() {
-	for _, a := range genList {
-		err := scr.GenerateFromAny(a, &config)
+	report, err := scr.GenerateAll(genList, &config)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	for _, p := range genPackages {
+		r, err := scr.GenerateFromPackage(p, &config)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		report.Merge(r)
+	}
+	// 写出生成清单，清理上游已删除的符号留下的文件
+	if _, err := scr.SyncManifest(&config, report); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	// 无法生成的符号不中断流程，最后统一输出
+	if report.HasDiagnostics() {
+		fmt.Println(report)
 	}
 }
@@ -11,7 +11,7 @@ func buildClass(t reflect.Type, cache *GroupCache, config *Config) error {
 	// 验证和预处理类型（支持 struct/interface）
 	structType, err := validateAndPrepareStructType(t)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// 收集导出方法（支持 struct/interface）
@@ -23,23 +23,21 @@ func buildClass(t reflect.Type, cache *GroupCache, config *Config) error {
 	// 接口无字段，跳过字段递归检查
 	if structType.Kind() != reflect.Interface {
 		checkFieldsRecursiveGeneration(structType, cache)
+		reportUnimportableFields(structType, cache)
 	}
 
+	// 先生成方法文件，失败的方法记录诊断并从类中移除
+	generateMethodFiles(structType, allMethods, cache)
+
 	// 生成类文件
 	if err := generateClassFile(structType, allMethods, cache); err != nil {
-		panic(err)
+		return fmt.Errorf("生成类文件失败: %w", err)
 	}
 
-	// 生成方法文件
-	if err := generateMethodFiles(structType, allMethods, cache); err != nil {
-		panic(err)
-	}
-
-	// 注册类并生成 load.go
-	globalCache.RegisterClass(pkgBaseName(structType.PkgPath()), structType.Name())
-	if err := emitLoadFile(pkgBaseName(structType.PkgPath()), cache); err != nil {
-		panic(err)
-	}
+	// 注册类，load.go 在全部生成结束后统一写出
+	pkgName := outputPackageName(structType.PkgPath(), config)
+	globalCache.RegisterClass(pkgName, typeIdentName(structType))
+	cache.markLoadPackage(pkgName)
 
 	return nil
 }
@@ -106,34 +104,51 @@ func checkFieldsRecursiveGeneration(structType reflect.Type, cache *GroupCache)
 	}
 }
 
+// reportUnimportableFields 为类型无法导入的导出字段记录诊断，这些字段不生成属性（见 collectClassFields）
+func reportUnimportableFields(structType reflect.Type, cache *GroupCache) {
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || isEmbeddedStruct(field) {
+			continue
+		}
+		if err := checkImportable(field.Type); err != nil {
+			cache.Report.Add(structType.String()+"."+field.Name, err)
+		}
+	}
+}
+
 // checkFieldRecursiveGeneration 检查单个字段的递归生成
 func checkFieldRecursiveGeneration(field reflect.StructField, cache *GroupCache) {
 	fieldType := field.Type
 
 	// 接口类型
 	if fieldType.Kind() == reflect.Interface && fieldType.PkgPath() != "" && fieldType.Name() != "" {
-		_ = generateFromType(fieldType, cache, nil)
+		generateDependency(fieldType, cache)
 		return
 	}
 
 	// *struct 类型
 	if isPtrToStruct(fieldType) {
-		_ = generateFromType(fieldType, cache, nil)
+		generateDependency(fieldType, cache)
 		return
 	}
 
 	// 值 struct 类型
 	if fieldType.Kind() == reflect.Struct && fieldType.PkgPath() != "" && fieldType.Name() != "" {
-		_ = generateFromType(reflect.PointerTo(fieldType), cache, nil)
+		generateDependency(reflect.PointerTo(fieldType), cache)
 		return
 	}
+
+	// 切片、map 等容器的元素类型
+	if fieldType.Kind() != reflect.Func {
+		checkWrappedRecursiveGeneration(fieldType, cache)
+	}
 }
 
 // generateClassFile 生成类文件
 func generateClassFile(structType reflect.Type, allMethods map[string]reflect.Method, cache *GroupCache) error {
 	srcPkgPath := structType.PkgPath()
-	pkgName := pkgBaseName(srcPkgPath)
-	typeName := structType.Name()
+	pkgName := outputPackageName(srcPkgPath, cache.Config)
+	typeName := typeIdentName(structType)
 
 	// 生成类文件路径
 	outDir := filepath.Join(cache.Config.OutputRoot, pkgName)
@@ -143,43 +158,79 @@ func generateClassFile(structType reflect.Type, allMethods map[string]reflect.Me
 	fileCache := NewFileCache()
 
 	// 构建类文件内容
-	classBody := buildClassFileBody(srcPkgPath, pkgName, typeName, allMethods, structType, cache.Config.NamePrefix, fileCache, cache.Config)
+	classBody := buildClassFileBody(srcPkgPath, pkgName, typeName, allMethods, structType, scriptNamespace(srcPkgPath, cache.Config), fileCache, cache.Config)
 
 	// 输出文件
-	return emitFile(classFile, pkgName, classBody)
+	return emitFile(classFile, pkgName, classBody, cache)
 }
 
 // generateMethodFiles 生成方法文件
-func generateMethodFiles(structType reflect.Type, allMethods map[string]reflect.Method, cache *GroupCache) error {
+// 单个方法生成失败时记录诊断，并将其从 allMethods 中移除，避免类文件引用不存在的方法
+func generateMethodFiles(structType reflect.Type, allMethods map[string]reflect.Method, cache *GroupCache) {
+	// 仅为冲突消解后的选中方法生成文件
+	selected := buildMethodFieldMapping(allMethods)
+	for _, keyName := range sortedKeys(selected) {
+		chosenName := selected[keyName]
+		if err := generateMethodFile(structType, allMethods[chosenName], cache); err != nil {
+			cache.Report.Add(structType.String()+"."+chosenName, err)
+			removeMethodGroup(allMethods, chosenName)
+		}
+	}
+}
+
+// generateMethodFile 生成单个方法文件，异常签名导致的 panic 转为 error
+func generateMethodFile(structType reflect.Type, method reflect.Method, cache *GroupCache) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("生成方法时发生异常: %v", r)
+		}
+	}()
+
+	// 签名引用了无法导入的类型时生成的代码无法编译
+	if err := checkImportable(method.Type); err != nil {
+		return err
+	}
+
 	srcPkgPath := structType.PkgPath()
-	pkgName := pkgBaseName(srcPkgPath)
-	typeName := structType.Name()
+	pkgName := outputPackageName(srcPkgPath, cache.Config)
+	typeName := typeIdentName(structType)
 
 	// 生成方法文件路径
 	outDir := filepath.Join(cache.Config.OutputRoot, pkgName)
+	methodFile := filepath.Join(outDir, strings.ToLower(typeName)+"_"+strings.ToLower(method.Name)+"_method.go")
 
 	// 结构体方法使用指针接收者；接口方法没有接收者
 	sourceIsPtr := structType.Kind() == reflect.Struct
-	// 仅为冲突消解后的选中方法生成文件
-	selected := buildMethodFieldMapping(allMethods)
-	for _, chosenName := range selected {
-		method := allMethods[chosenName]
-		methodFile := filepath.Join(outDir, strings.ToLower(typeName)+"_"+strings.ToLower(chosenName)+"_method.go")
 
-		// 创建文件缓存
-		fileCache := NewFileCache()
+	// 多返回值的结果类（属性名取源码中的返回值名）
+	scope := sourceParamIndex(srcPkgPath)
+	sourceNames, _ := scope.methodSignature(typeDeclName(structType), method.Name)
+	result := newResultClass(typeName+method.Name, scriptNamespace(srcPkgPath, cache.Config), analyzeMethodReturns(method), sourceNames.results, scope)
 
-		// 构建方法文件内容
-		methodBody, ok := buildMethodFileBody(srcPkgPath, pkgName, typeName, method, sourceIsPtr, fileCache, structType, cache.Config)
-		if !ok {
-			continue
-		}
+	// 创建文件缓存
+	fileCache := NewFileCache()
 
-		// 输出文件
-		if err := emitFile(methodFile, pkgName, methodBody); err != nil {
-			return err
-		}
+	// 构建方法文件内容
+	methodBody, ok := buildMethodFileBody(srcPkgPath, pkgName, typeName, method, sourceIsPtr, result, fileCache, structType, cache.Config)
+	if !ok {
+		return fmt.Errorf("方法签名不支持: %s", method.Type.String())
 	}
 
+	// 输出文件
+	if err := emitFile(methodFile, pkgName, methodBody, cache); err != nil {
+		return err
+	}
+	if result != nil {
+		return generateResultClassFile(result, strings.ToLower(typeName)+"_"+strings.ToLower(method.Name), pkgName, cache)
+	}
 	return nil
 }
+
+// removeMethodGroup 移除与 name 归一后同名的全部方法（与 buildMethodFieldMapping 的分组一致）
+func removeMethodGroup(allMethods map[string]reflect.Method, name string) {
+	for methodName := range allMethods {
+		if strings.EqualFold(methodName, name) {
+			delete(allMethods, methodName)
+		}
+	}
+}
@@ -10,118 +10,102 @@ import (
 
 func buildFunc(t reflect.Type, cache *GroupCache, originalValue any) error {
 	if t.Kind() != reflect.Func {
-		panic(fmt.Errorf("期望函数类型，实际: %s", t.String()))
+		return fmt.Errorf("期望函数类型，实际: %s", t.String())
+	}
+
+	funcName, err := getFunctionName(t, originalValue)
+	if err != nil {
+		return err
+	}
+	pkgName, err := getFunctionPackageName(t, originalValue, cache.Config)
+	if err != nil {
+		return err
 	}
 
 	// 检查函数参数和返回值，看是否需要生成代理类
 	checkFunctionRecursiveGeneration(t, cache)
 
 	// 生成函数文件
-	if err := generateFunctionFile(t, cache, originalValue); err != nil {
-		panic(err)
+	if err := generateFunctionFile(t, cache, originalValue, funcName, pkgName); err != nil {
+		return fmt.Errorf("生成函数文件失败: %w", err)
 	}
 
-	// 注册函数并生成 load.go
-	funcName, err := getFunctionName(t, originalValue)
-	if err != nil {
-		panic(err)
-	}
-	if funcName != "" {
-		pkgName := getFunctionPackageName(t, originalValue)
-		globalCache.RegisterFunction(pkgName, funcName)
-		if err := emitLoadFile(pkgName, cache); err != nil {
-			panic(err)
-		}
-	}
+	// 注册函数，load.go 在全部生成结束后统一写出
+	globalCache.RegisterFunction(pkgName, funcName)
+	cache.markLoadPackage(pkgName)
 
 	return nil
 }
 
 // generateFunctionFile 生成函数文件
-func generateFunctionFile(t reflect.Type, cache *GroupCache, originalValue any) error {
-	funcName, err := getFunctionName(t, originalValue)
-	if err != nil {
-		panic(err)
-	}
-
+func generateFunctionFile(t reflect.Type, cache *GroupCache, originalValue any, funcName, pkgName string) error {
 	// 获取包信息
-	pkgName := getFunctionPackageName(t, originalValue)
-	srcPkgPath := getFunctionPackagePath(t)
-	namePrefix := cache.Config.NamePrefix
+	srcPkgPath := getFunctionPackagePath(t, originalValue)
+	namePrefix := scriptNamespace(srcPkgPath, cache.Config)
 
 	// 生成函数文件路径
 	outDir := filepath.Join(cache.Config.OutputRoot, pkgName)
 	funcFile := filepath.Join(outDir, strings.ToLower(funcName)+"_func.go")
 
+	// 泛型函数实例按声明名查找源码信息，并以带类型实参的表达式调用
+	generic, _ := originalValue.(GenericFunc)
+	declName := funcName
+	if generic.Name != "" {
+		declName = generic.Name
+	}
+
+	// 多返回值的结果类（属性名取源码中的返回值名）
+	scope := sourceParamIndex(srcPkgPath)
+	sourceNames, _ := scope.funcSignature(declName)
+	result := newResultClass(funcName, namePrefix, analyzeFunctionReturns(t), sourceNames.results, scope)
+
 	// 创建文件缓存
 	fileCache := NewFileCache()
 
 	// 构建函数文件内容（传入源包路径以保证 import alias 一致）
-	funcBody := buildFunctionFileBody(srcPkgPath, pkgName, namePrefix, funcName, t, fileCache, cache.Config)
+	funcBody := buildFunctionFileBody(srcPkgPath, pkgName, namePrefix, funcName, t, result, &generic, fileCache, cache.Config)
 
 	// 输出文件
-	return emitFile(funcFile, pkgName, funcBody)
+	if err := emitFile(funcFile, pkgName, funcBody, cache); err != nil {
+		return err
+	}
+	if result != nil {
+		return generateResultClassFile(result, strings.ToLower(funcName), pkgName, cache)
+	}
+	return nil
+}
+
+// getFunctionPackageName 从函数推断输出子包名
+func getFunctionPackageName(t reflect.Type, originalValue any, config *Config) (string, error) {
+	pkgPath := getFunctionPackagePath(t, originalValue)
+	if pkgPath == "" {
+		return "", fmt.Errorf("无法从函数类型 %s 推断包名", t.String())
+	}
+	return outputPackageName(pkgPath, config), nil
 }
 
-// getFunctionPackageName 从函数类型推断包名
-func getFunctionPackageName(t reflect.Type, originalValue any) string {
+// getFunctionPackagePath 从函数推断完整包路径
+func getFunctionPackagePath(t reflect.Type, originalValue any) string {
 	// 先用函数类型自身的包路径（命名函数类型）
 	if t.PkgPath() != "" {
-		return pkgBaseName(t.PkgPath())
+		return t.PkgPath()
 	}
 
-	// 尝试从 originalValue 中获取包信息
+	// 泛型函数实例由调用方提供包路径
+	if g, ok := originalValue.(GenericFunc); ok {
+		return g.PkgPath
+	}
+
+	// 尝试从 originalValue 的真实函数名中获取包路径
 	if originalValue != nil {
 		if f := runtime.FuncForPC(reflect.ValueOf(originalValue).Pointer()); f != nil {
-			name := f.Name()
-			if idx := strings.LastIndex(name, "."); idx >= 0 {
-				// 提取包名（去掉函数名部分）
-				pkgPath := name[:idx]
-				// 处理嵌套包的情况，取最后一部分作为包名
-				if lastDot := strings.LastIndex(pkgPath, "/"); lastDot >= 0 {
-					return pkgPath[lastDot+1:]
-				}
+			if pkgPath := funcPackagePath(f.Name()); pkgPath != "" {
 				return pkgPath
 			}
 		}
 	}
 
 	// 尝试从参数和返回值中获取包信息
-	for i := 0; i < t.NumIn(); i++ {
-		paramType := t.In(i)
-		if pkgPath := paramType.PkgPath(); pkgPath != "" {
-			return pkgBaseName(pkgPath)
-		}
-		// 检查指针类型的元素
-		if paramType.Kind() == reflect.Ptr && paramType.Elem() != nil {
-			if pkgPath := paramType.Elem().PkgPath(); pkgPath != "" {
-				return pkgBaseName(pkgPath)
-			}
-		}
-	}
-
-	for i := 0; i < t.NumOut(); i++ {
-		returnType := t.Out(i)
-		if pkgPath := returnType.PkgPath(); pkgPath != "" {
-			return pkgBaseName(pkgPath)
-		}
-		// 检查指针类型的元素
-		if returnType.Kind() == reflect.Ptr && returnType.Elem() != nil {
-			if pkgPath := returnType.Elem().PkgPath(); pkgPath != "" {
-				return pkgBaseName(pkgPath)
-			}
-		}
-	}
-
-	// 如果无法推断，直接抛出错误
-	panic(fmt.Sprintf("无法从函数类型 %s 推断包名", t.String()))
-}
-
-// getFunctionPackagePath 从函数类型推断完整包路径
-func getFunctionPackagePath(t reflect.Type) string {
-	if t.PkgPath() != "" {
-		return t.PkgPath()
-	}
 	for i := 0; i < t.NumIn(); i++ {
 		paramType := t.In(i)
 		if pkgPath := paramType.PkgPath(); pkgPath != "" {
@@ -147,8 +131,24 @@ func getFunctionPackagePath(t reflect.Type) string {
 	return ""
 }
 
+// funcPackagePath 从 runtime 函数全名中截取包路径
+// 例如 "github.com/redis/go-redis/v9.NewClient" -> "github.com/redis/go-redis/v9"；
+// 最后一段中的 "." 在符号名中被转义为 "%2e"（如 gopkg.in/yaml%2ev3.Marshal）
+func funcPackagePath(fullName string) string {
+	slash := strings.LastIndex(fullName, "/")
+	dot := strings.Index(fullName[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return strings.ReplaceAll(fullName[:slash+1+dot], "%2e", ".")
+}
+
 // getFunctionName 获取函数名（优先真实函数名，回退到类型/签名推断）
 func getFunctionName(t reflect.Type, originalValue any) (string, error) {
+	// 泛型函数实例的 runtime 名称形如 Map[...]，使用 基础名+类型实参
+	if g, ok := originalValue.(GenericFunc); ok {
+		return g.identName(), nil
+	}
 	// 优先使用 runtime.FuncForPC 获取真实函数名
 	if originalValue != nil {
 		if f := runtime.FuncForPC(reflect.ValueOf(originalValue).Pointer()); f != nil {
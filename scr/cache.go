@@ -1,5 +1,7 @@
 package scr
 
+import "sync"
+
 type Use struct {
 	alias string
 	path  string
@@ -23,11 +25,17 @@ func NewFileCache() *FileCache {
 }
 
 // AddImport 添加导入
+// 已导入的包保留首次的别名与使用状态：源包以 xxxsrc 别名导入后，
+// 通用导入（如 time）不能覆盖别名，否则已写出的 timesrc.Xxx 引用失效
 func (fc *FileCache) AddImport(pkgPath, alias string) {
-	if pkgPath != "" {
-		fc.Imports[pkgPath] = alias
-		fc.ImportUsage[pkgPath] = false // 初始化为未使用
+	if pkgPath == "" {
+		return
 	}
+	if _, ok := fc.Imports[pkgPath]; ok {
+		return
+	}
+	fc.Imports[pkgPath] = alias
+	fc.ImportUsage[pkgPath] = false // 初始化为未使用
 }
 
 // MarkImportUsed 标记导入为已使用
@@ -58,6 +66,14 @@ type GroupCache struct {
 	CurrentDepth int
 	// 已生成的类型缓存，防止重复生成和死循环
 	generatedTypes map[string]bool
+	// 需要在生成结束后写出 load.go 的输出子包
+	loadPackages map[string]bool
+	// 保护 generatedTypes 与 loadPackages，各 worker 并发访问
+	mu sync.Mutex
+	// 并行生成的 worker 池
+	pool *workerPool
+	// 生成报告，收集无法生成的符号
+	Report *Report
 }
 
 // NewGroupCache 创建新的 GroupCache 实例
@@ -66,21 +82,55 @@ func NewGroupCache(config *Config) *GroupCache {
 		Config:         config,
 		CurrentDepth:   0,
 		generatedTypes: make(map[string]bool),
+		loadPackages:   make(map[string]bool),
+		pool:           newWorkerPool(configWorkers(config)),
+		Report:         &Report{},
 	}
 }
 
 // IsTypeGenerated 检查类型是否已生成
 func (gc *GroupCache) IsTypeGenerated(typeKey string) bool {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
 	return gc.generatedTypes[typeKey]
 }
 
 // MarkTypeGenerated 标记类型为已生成
 func (gc *GroupCache) MarkTypeGenerated(typeKey string) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.generatedTypes[typeKey] = true
+}
+
+// tryMarkTypeGenerated 原子地检查并标记类型，首次标记时返回 true
+// 并发时保证同一类型只被一个 worker 生成
+func (gc *GroupCache) tryMarkTypeGenerated(typeKey string) bool {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.generatedTypes[typeKey] {
+		return false
+	}
 	gc.generatedTypes[typeKey] = true
+	return true
+}
+
+// markLoadPackage 记录需要写出 load.go 的输出子包
+func (gc *GroupCache) markLoadPackage(pkgName string) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.loadPackages[pkgName] = true
+}
+
+// listLoadPackages 返回需要写出 load.go 的输出子包（已排序）
+func (gc *GroupCache) listLoadPackages() []string {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return sortedKeys(gc.loadPackages)
 }
 
 // GlobalCache 全局缓存管理器
 type GlobalCache struct {
+	mu sync.Mutex
 	// 包级别的缓存
 	packageCaches map[string]*PackageCache
 }
@@ -94,6 +144,13 @@ func NewGlobalCache() *GlobalCache {
 
 // GetPackageCache 获取包缓存
 func (gc *GlobalCache) GetPackageCache(pkgName string) *PackageCache {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.packageCache(pkgName)
+}
+
+// packageCache 获取包缓存，调用方需持有锁
+func (gc *GlobalCache) packageCache(pkgName string) *PackageCache {
 	if cache, exists := gc.packageCaches[pkgName]; exists {
 		return cache
 	}
@@ -108,7 +165,9 @@ func (gc *GlobalCache) GetPackageCache(pkgName string) *PackageCache {
 
 // RegisterClass 注册类到包缓存
 func (gc *GlobalCache) RegisterClass(pkgName, typeName string) {
-	cache := gc.GetPackageCache(pkgName)
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	cache := gc.packageCache(pkgName)
 	cache.Load[typeName] = Load{
 		name:     typeName,
 		typeName: "class",
@@ -117,18 +176,23 @@ func (gc *GlobalCache) RegisterClass(pkgName, typeName string) {
 
 // RegisterFunction 注册函数到包缓存
 func (gc *GlobalCache) RegisterFunction(pkgName, funcName string) {
-	cache := gc.GetPackageCache(pkgName)
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	cache := gc.packageCache(pkgName)
 	cache.Load[funcName] = Load{
 		name:     funcName,
 		typeName: "func",
 	}
 }
 
-// ListRegistered 列出包中已注册的类型和函数
+// ListRegistered 列出包中已注册的类型和函数（按名称排序）
 func (gc *GlobalCache) ListRegistered(pkgName string) (classes, functions []string) {
-	cache := gc.GetPackageCache(pkgName)
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	cache := gc.packageCache(pkgName)
 
-	for name, load := range cache.Load {
+	for _, name := range sortedKeys(cache.Load) {
+		load := cache.Load[name]
 		if load.typeName == "class" {
 			classes = append(classes, name)
 		} else if load.typeName == "func" {
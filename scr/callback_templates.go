@@ -0,0 +1,133 @@
+package scr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CallbackTemplates 函数类型参数适配模块
+//
+// Go 函数类型参数（如 func(*User) bool）无法直接从脚本值转换，
+// 这里为其生成适配闭包：Go 侧调用时把实参封装为脚本值，经 utils.Callback 执行脚本回调，
+// 再把回调返回值转换回 Go 类型。回调抛出的异常作为 error 返回给 Go 代码，
+// 并在 Go 函数返回后重新抛回脚本；回调没有 error 返回值时，返回值转换失败同样记录并抛回脚本。
+
+// isCallbackParam 判断参数能否生成回调适配闭包：
+// 非可变参数的匿名函数类型或导出的具名函数类型，且参数与返回值类型都能在生成文件中书写
+// 未导出或位于 internal 包的具名函数类型无法引用，引用它们的符号由 checkImportable 记录诊断并跳过
+func isCallbackParam(t reflect.Type, fileCache *FileCache) bool {
+	if t.Kind() != reflect.Func || t.IsVariadic() {
+		return false
+	}
+	if bad, _ := unimportableType(t); bad != nil {
+		return false
+	}
+	for i := 0; i < t.NumIn(); i++ {
+		if in := t.In(i); !isBuiltinErrorType(in) && !isWritableType(in, fileCache) {
+			return false
+		}
+	}
+	values, _ := splitErrorReturn(typesOut(t))
+	for _, out := range values {
+		if !isWritableType(out, fileCache) {
+			return false
+		}
+	}
+	return true
+}
+
+// typesOut 返回函数类型的全部返回值类型
+func typesOut(t reflect.Type) []reflect.Type {
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return out
+}
+
+// callbackVarName 回调参数对应的 *utils.Callback 变量名
+func callbackVarName(pName string) string {
+	return pName + "Callback"
+}
+
+// writeCallbackConversion 写入函数类型参数的转换代码
+// 生成 pName, pNameCallback, err := utils.FuncFromIndex(ctx, i, func(cb *utils.Callback) F { ... })
+func writeCallbackConversion(b *strings.Builder, t reflect.Type, pName string, ctxIndex int, fileCache *FileCache, origPkgName, importAlias, srcPkgPath string, config *Config) {
+	typeStr := func(t reflect.Type) string {
+		s := getTypeString(t, fileCache)
+		if origPkgName != "" && strings.Contains(s, origPkgName+".") {
+			s = strings.ReplaceAll(s, origPkgName+".", importAlias+".")
+		}
+		return s
+	}
+	values, hasErr := splitErrorReturn(typesOut(t))
+
+	// 适配闭包签名：实参 argN，具名返回值 rN / err，回调失败时直接 return 零值
+	args := make([]string, 0, t.NumIn())
+	wrapped := make([]string, 0, t.NumIn())
+	for i := 0; i < t.NumIn(); i++ {
+		arg := fmt.Sprintf("arg%d", i)
+		markTypeImportsUsed(t.In(i), fileCache, "")
+		args = append(args, arg+" "+typeStr(t.In(i)))
+		wrapped = append(wrapped, returnValueExpr(t.In(i), arg, srcPkgPath, config, fileCache))
+	}
+	results := make([]string, 0, t.NumOut())
+	for i, out := range values {
+		markTypeImportsUsed(out, fileCache, "")
+		results = append(results, fmt.Sprintf("r%d %s", i, typeStr(out)))
+	}
+	if hasErr {
+		results = append(results, "err error")
+	}
+
+	fmt.Fprintf(b, "\t%s, %s, err := utils.FuncFromIndex(ctx, %d, func(cb *utils.Callback) %s {\n", pName, callbackVarName(pName), ctxIndex, typeStr(t))
+	fmt.Fprintf(b, "\t\treturn func(%s)", strings.Join(args, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(results, ", "))
+	}
+	b.WriteString(" {\n")
+
+	call := fmt.Sprintf("cb.Call(%s)", strings.Join(wrapped, ", "))
+	switch {
+	case len(values) > 0:
+		fmt.Fprintf(b, "\t\t\tret, err := %s\n", call)
+		b.WriteString("\t\t\tif err != nil {\n\t\t\t\treturn\n\t\t\t}\n")
+		for i, out := range values {
+			// 单个返回值直接转换；多个返回值由脚本以数组返回
+			src := "ret"
+			if len(values) > 1 {
+				src = fmt.Sprintf("utils.IndexValue(ret, %d)", i)
+			}
+			fmt.Fprintf(b, "\t\t\tif r%d, err = utils.Convert[%s](%s); err != nil {\n", i, typeStr(out), src)
+			// 没有 error 返回值时无法告知 Go 调用方，记录到回调，Go 函数返回后抛回脚本
+			if !hasErr {
+				b.WriteString("\t\t\t\tcb.Fail(err)\n")
+			}
+			b.WriteString("\t\t\t\treturn\n\t\t\t}\n")
+		}
+		b.WriteString("\t\t\treturn\n")
+	case hasErr:
+		fmt.Fprintf(b, "\t\t\t_, err = %s\n", call)
+		b.WriteString("\t\t\treturn\n")
+	default:
+		fmt.Fprintf(b, "\t\t\t%s\n", call)
+	}
+	b.WriteString("\t\t}\n")
+	b.WriteString("\t})\n")
+}
+
+// writeCallbackChecks 写入回调异常检查：Go 函数返回后，回调中记录的脚本异常优先抛回脚本
+func writeCallbackChecks(b *strings.Builder, indent string, paramTypes []reflect.Type, paramNames []string, isVariadic bool, fileCache *FileCache) {
+	for i, pName := range paramNames {
+		if isVariadic && i == len(paramNames)-1 {
+			break
+		}
+		if !isCallbackParam(paramTypes[i], fileCache) {
+			continue
+		}
+		fmt.Fprintf(b, "%sif ctl := %s.Control(); ctl != nil {\n", indent, callbackVarName(pName))
+		fmt.Fprintf(b, "%s\treturn nil, ctl\n", indent)
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
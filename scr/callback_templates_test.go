@@ -0,0 +1,35 @@
+package scr
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Predicate、predicate 测试用导出与未导出的具名函数类型
+type (
+	Predicate func(int) bool
+	predicate func(int) bool
+)
+
+func TestIsCallbackParam(t *testing.T) {
+	tests := []struct {
+		name string
+		t    reflect.Type
+		want bool
+	}{
+		{"匿名函数类型", reflect.TypeFor[func(int, string) (bool, error)](), true},
+		{"导出的具名函数类型", reflect.TypeFor[Predicate](), true},
+		{"未导出的具名函数类型", reflect.TypeFor[predicate](), false},
+		{"参数为未导出类型", reflect.TypeFor[func(hidden)](), false},
+		{"可变参数", reflect.TypeFor[func(...int)](), false},
+		{"非函数类型", reflect.TypeFor[int](), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCallbackParam(tt.t, NewFileCache()); got != tt.want {
+				t.Errorf("isCallbackParam(%s) = %v, 期望 %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
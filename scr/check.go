@@ -0,0 +1,90 @@
+package scr
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Check 校验模块，对比内存中重新生成的文件与磁盘内容，发现过期的绑定
+
+// CheckResult 生成结果与磁盘内容的差异
+type CheckResult struct {
+	// 内容不一致的文件
+	Changed []string
+	// 应生成但磁盘上不存在的文件
+	Missing []string
+	// 清单中记录、带生成标记、但本次没有生成的文件（即 SyncManifest 将删除的孤立文件）
+	Extra []string
+}
+
+// HasDifferences 是否存在差异
+func (r *CheckResult) HasDifferences() bool {
+	return r != nil && len(r.Changed)+len(r.Missing)+len(r.Extra) > 0
+}
+
+func (r *CheckResult) String() string {
+	if !r.HasDifferences() {
+		return "生成结果与磁盘一致"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "生成结果与磁盘不一致（变更 %d，缺失 %d，多余 %d）:", len(r.Changed), len(r.Missing), len(r.Extra))
+	for _, group := range []struct {
+		label string
+		files []string
+	}{
+		{"变更", r.Changed},
+		{"缺失", r.Missing},
+		{"多余", r.Extra},
+	} {
+		for _, f := range group.files {
+			fmt.Fprintf(b, "\n  %s: %s", group.label, f)
+		}
+	}
+	return b.String()
+}
+
+// CheckOutput 对比内存中生成的文件与磁盘内容
+// 多余文件按清单判断，与 SyncManifest 将删除的孤立文件一致；清单文件本身也参与对比
+func CheckOutput(config *Config, generated *MemoryFS, report *Report) (*CheckResult, error) {
+	result := &CheckResult{}
+
+	for _, p := range generated.Paths() {
+		want, _ := generated.ReadFile(p)
+		if err := result.compare(p, want); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := planManifest(config, report)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.compare(plan.path, plan.content); err != nil {
+		return nil, err
+	}
+	result.Extra = append(result.Extra, plan.orphans...)
+
+	sort.Strings(result.Changed)
+	sort.Strings(result.Missing)
+	sort.Strings(result.Extra)
+	return result, nil
+}
+
+// compare 对比单个文件的期望内容与磁盘内容，记录缺失或变更
+func (r *CheckResult) compare(p string, want []byte) error {
+	got, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		r.Missing = append(r.Missing, p)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		r.Changed = append(r.Changed, p)
+	}
+	return nil
+}
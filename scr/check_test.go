@@ -0,0 +1,113 @@
+package scr
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		disk      map[string]string
+		generated map[string]string
+		packages  []string
+		changed   []string
+		missing   []string
+		extra     []string
+	}{
+		{
+			name: "与磁盘一致",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/x.go"),
+				"a/x.go":     testGenerated,
+			},
+			generated: map[string]string{"a/x.go": testGenerated},
+			packages:  []string{"example.com/a"},
+		},
+		{
+			name: "文件内容变更",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/x.go"),
+				"a/x.go":     testHandWrite,
+			},
+			generated: map[string]string{"a/x.go": testGenerated},
+			packages:  []string{"example.com/a"},
+			changed:   []string{"a/x.go"},
+		},
+		{
+			name: "缺失文件与清单",
+			disk: map[string]string{
+				"a/x.go": testGenerated,
+			},
+			generated: map[string]string{"a/x.go": testGenerated, "a/y.go": testGenerated},
+			packages:  []string{"example.com/a"},
+			missing:   []string{"a/y.go", ManifestName},
+		},
+		{
+			name: "清单中的孤立文件为多余文件",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/old.go", "a/x.go"),
+				"a/x.go":     testGenerated,
+				"a/old.go":   testGenerated,
+			},
+			generated: map[string]string{"a/x.go": testGenerated},
+			packages:  []string{"example.com/a"},
+			changed:   []string{ManifestName},
+			extra:     []string{"a/old.go"},
+		},
+		{
+			name: "未记录在清单中的带标记文件不算多余",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/x.go"),
+				"a/x.go":     testGenerated,
+				"a/stray.go": testGenerated,
+			},
+			generated: map[string]string{"a/x.go": testGenerated},
+			packages:  []string{"example.com/a"},
+		},
+		{
+			name: "其他包的条目不算多余",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/x.go", "[b]", "b/z.go"),
+				"a/x.go":     testGenerated,
+				"b/z.go":     testGenerated,
+			},
+			generated: map[string]string{"a/x.go": testGenerated},
+			packages:  []string{"example.com/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tt.disk)
+			config := &Config{OutputRoot: root}
+
+			generated := NewMemoryFS()
+			var files []string
+			for rel, content := range tt.generated {
+				files = append(files, rel)
+				if err := generated.WriteFile(filepath.Join(root, rel), []byte(content)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := CheckOutput(config, generated, testReport(root, files, tt.packages...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &CheckResult{
+				Changed: rootPaths(root, tt.changed),
+				Missing: rootPaths(root, tt.missing),
+				Extra:   rootPaths(root, tt.extra),
+			}
+			if !reflect.DeepEqual(result, want) {
+				t.Errorf("CheckOutput() = %+v, 期望 %+v", result, want)
+			}
+			if result.HasDifferences() != (len(tt.changed)+len(tt.missing)+len(tt.extra) > 0) {
+				t.Errorf("HasDifferences() = %v", result.HasDifferences())
+			}
+		})
+	}
+}
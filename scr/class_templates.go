@@ -24,6 +24,11 @@ func buildClassFileBody(srcPkgPath, pkgName, typeName string, methods map[string
 	// 生成类方法
 	writeClassMethods(b, namePrefix, typeName, methods, structType, importAlias, config, fileCache, srcPkgPath)
 
+	// 接口类注册到 utils，供其他类的 GetImplements 匹配
+	if structType.Kind() == reflect.Interface {
+		writeInterfaceRegistration(b, namePrefix, typeName, structType, importAlias, fileCache)
+	}
+
 	// 在文件开头写入导入（在代码生成完成后，但需要插入到文件开头）
 	content := b.String()
 	b.Reset()
@@ -46,7 +51,9 @@ func writeClassConstructor(b *strings.Builder, typeName, importAlias string, met
 	fmt.Fprintf(b, "func New%sClass() data.ClassStmt {\n", typeName)
 	fmt.Fprintf(b, "\treturn &%sClass{\n", typeName)
 	fmt.Fprintf(b, "\t\tsource: nil,\n")
-	for keyName, chosenMethod := range buildMethodFieldMapping(methods) {
+	mapping := buildMethodFieldMapping(methods)
+	for _, keyName := range sortedKeys(mapping) {
+		chosenMethod := mapping[keyName]
 		safeName := sanitizeIdentifier(keyName)
 		fmt.Fprintf(b, "\t\t%s: &%s%sMethod{source: nil},\n", safeName, typeName, chosenMethod)
 	}
@@ -55,13 +62,14 @@ func writeClassConstructor(b *strings.Builder, typeName, importAlias string, met
 
 	// NewXxxClassFrom() 构造函数
 	if structType.Kind() == reflect.Interface {
-		fmt.Fprintf(b, "func New%sClassFrom(source %s.%s) data.ClassStmt {\n", typeName, importAlias, typeName)
+		fmt.Fprintf(b, "func New%sClassFrom(source %s.%s) data.ClassStmt {\n", typeName, importAlias, genericTypeName(structType, fileCache))
 	} else {
-		fmt.Fprintf(b, "func New%sClassFrom(source *%s.%s) data.ClassStmt {\n", typeName, importAlias, typeName)
+		fmt.Fprintf(b, "func New%sClassFrom(source *%s.%s) data.ClassStmt {\n", typeName, importAlias, genericTypeName(structType, fileCache))
 	}
 	fmt.Fprintf(b, "\treturn &%sClass{\n", typeName)
 	fmt.Fprintf(b, "\t\tsource: source,\n")
-	for keyName, chosenMethod := range buildMethodFieldMapping(methods) {
+	for _, keyName := range sortedKeys(mapping) {
+		chosenMethod := mapping[keyName]
 		safeName := sanitizeIdentifier(keyName)
 		fmt.Fprintf(b, "\t\t%s: &%s%sMethod{source: source},\n", safeName, typeName, chosenMethod)
 	}
@@ -82,13 +90,13 @@ func writeClassStruct(b *strings.Builder, typeName string, methods map[string]re
 
 	// 根据类型决定 source 字段类型
 	if structType.Kind() == reflect.Interface {
-		fmt.Fprintf(b, "\tsource %s.%s\n", importAlias, typeName)
+		fmt.Fprintf(b, "\tsource %s.%s\n", importAlias, genericTypeName(structType, fileCache))
 	} else {
-		fmt.Fprintf(b, "\tsource *%s.%s\n", importAlias, typeName)
+		fmt.Fprintf(b, "\tsource *%s.%s\n", importAlias, genericTypeName(structType, fileCache))
 	}
 
 	// 添加方法字段（小驼峰命名）
-	for keyName := range buildMethodFieldMapping(methods) {
+	for _, keyName := range sortedKeys(buildMethodFieldMapping(methods)) {
 		safeName := sanitizeIdentifier(keyName)
 		fmt.Fprintf(b, "\t%s data.Method\n", safeName)
 	}
@@ -109,18 +117,27 @@ func writeClassMethods(b *strings.Builder, namePrefix, typeName string, methods
 	if structType.Kind() == reflect.Interface {
 		fmt.Fprintf(b, "\treturn data.NewProxyValue(New%sClassFrom(nil), ctx.CreateBaseContext()), nil\n", typeName)
 	} else {
-		fmt.Fprintf(b, "\treturn data.NewProxyValue(New%sClassFrom(&%s.%s{}), ctx.CreateBaseContext()), nil\n", typeName, importAlias, typeName)
+		fmt.Fprintf(b, "\treturn data.NewProxyValue(New%sClassFrom(&%s.%s{}), ctx.CreateBaseContext()), nil\n", typeName, importAlias, genericTypeName(structType, fileCache))
 	}
 	b.WriteString("}\n\n")
 
 	// GetName 方法
 	fmt.Fprintf(b, "func (s *%sClass) GetName() string { return \"%s\\\\%s\" }\n", typeName, namePrefix, typeName)
 
-	// GetExtend 方法
-	fmt.Fprintf(b, "func (s *%sClass) GetExtend() *string { return nil }\n", typeName)
+	// GetExtend 方法：首个嵌入的生成类结构体作为父类
+	if parent := embeddedParentType(structType, config); parent != nil {
+		fmt.Fprintf(b, "func (s *%sClass) GetExtend() *string {\n", typeName)
+		fmt.Fprintf(b, "\textend := %q\n", scriptClassName(parent, config))
+		b.WriteString("\treturn &extend\n")
+		b.WriteString("}\n")
+	} else {
+		fmt.Fprintf(b, "func (s *%sClass) GetExtend() *string { return nil }\n", typeName)
+	}
 
-	// GetImplements 方法
-	fmt.Fprintf(b, "func (s *%sClass) GetImplements() []string { return nil }\n", typeName)
+	// GetImplements 方法：运行时按方法集匹配已注册的生成接口类
+	fileCache.MarkImportUsed("reflect")
+	fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+	fmt.Fprintf(b, "func (s *%sClass) GetImplements() []string { return utils.Implements(%s) }\n", typeName, classReflectType(structType, importAlias, fileCache))
 
 	// AsString 方法
 	fmt.Fprintf(b, "func (s *%sClass) AsString() string { return \"%s{}\" }\n", typeName, typeName)
@@ -138,14 +155,24 @@ func writeClassMethods(b *strings.Builder, namePrefix, typeName string, methods
 	fmt.Fprintf(b, "func (s *%sClass) GetConstruct() data.Method { return nil }\n\n", typeName)
 
 	// GetProperty 和 GetProperties 方法
-	writePropertyMethods(b, typeName, structType, importAlias, config, fileCache)
+	writePropertyMethods(b, typeName, structType, importAlias, config, fileCache, srcPkgPath)
+}
+
+// writeInterfaceRegistration 写入接口类的 init 注册
+func writeInterfaceRegistration(b *strings.Builder, namePrefix, typeName string, structType reflect.Type, importAlias string, fileCache *FileCache) {
+	fileCache.MarkImportUsed("reflect")
+	fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+
+	b.WriteString("func init() {\n")
+	fmt.Fprintf(b, "\tutils.RegisterInterface(\"%s\\\\%s\", %s)\n", namePrefix, typeName, classReflectType(structType, importAlias, fileCache))
+	b.WriteString("}\n\n")
 }
 
 // writeGetMethod 写入 GetMethod 方法
 func writeGetMethod(b *strings.Builder, typeName string, methods map[string]reflect.Method) {
 	fmt.Fprintf(b, "func (s *%sClass) GetMethod(name string) (data.Method, bool) {\n", typeName)
 	b.WriteString("\tswitch name {\n")
-	for keyName := range buildMethodFieldMapping(methods) {
+	for _, keyName := range sortedKeys(buildMethodFieldMapping(methods)) {
 		safeName := sanitizeIdentifier(keyName)
 		fmt.Fprintf(b, "\tcase \"%s\": return s.%s, true\n", keyName, safeName)
 	}
@@ -157,7 +184,7 @@ func writeGetMethods(b *strings.Builder, typeName string, methods map[string]ref
 	fmt.Fprintf(b, "func (s *%sClass) GetMethods() []data.Method {\n", typeName)
 	b.WriteString("\treturn []data.Method{\n")
 	first := true
-	for keyName := range buildMethodFieldMapping(methods) {
+	for _, keyName := range sortedKeys(buildMethodFieldMapping(methods)) {
 		if !first {
 			b.WriteString(",\n")
 		} else {
@@ -174,6 +201,7 @@ func writeGetMethods(b *strings.Builder, typeName string, methods map[string]ref
 }
 
 // buildMethodFieldMapping 将方法集合映射为 字段键名->选中的方法名
+// 结果为 map，写出时需按 sortedKeys 排序遍历，保证输出稳定
 // 规则：
 // - 键名：首次出现的 lowerFirst(methodName)
 // - 归一键：strings.ToLower(键名)
@@ -239,12 +267,14 @@ func countTrailingUpper(s string) int {
 }
 
 // writePropertyMethods 写入属性相关方法
-func writePropertyMethods(b *strings.Builder, typeName string, structType reflect.Type, importAlias string, config *Config, fileCache *FileCache) {
+// 属性每次读取都取源对象字段的当前值：生成类的字段返回类值，基础类型返回对应的脚本值
+func writePropertyMethods(b *strings.Builder, typeName string, structType reflect.Type, importAlias string, config *Config, fileCache *FileCache, srcPkgPath string) {
 	// 标记使用的导入
 	fileCache.MarkImportUsed("github.com/php-any/origami/data")
-	fileCache.MarkImportUsed("github.com/php-any/origami/node")
 
-	if structType == nil || structType.Kind() != reflect.Struct || structType.NumField() == 0 {
+	fields := collectClassFields(structType)
+
+	if len(fields) == 0 {
 		// 无字段时返回空实现
 		fmt.Fprintf(b, "func (s *%sClass) GetProperty(name string) (data.Property, bool) {\n", typeName)
 		fmt.Fprintf(b, "\treturn nil, false\n")
@@ -253,36 +283,28 @@ func writePropertyMethods(b *strings.Builder, typeName string, structType reflec
 		fmt.Fprintf(b, "func (s *%sClass) GetProperties() map[string]data.Property {\n", typeName)
 		fmt.Fprintf(b, "\treturn map[string]data.Property{}\n")
 		fmt.Fprintf(b, "}\n\n")
+
+		if structType != nil && structType.Kind() == reflect.Struct && structType.NumField() > 0 {
+			writeSetPropertyMethod(b, typeName, structType, importAlias, config, fileCache)
+		}
 		return
 	}
 
-	// GetProperty 方法
+	fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+
+	// GetProperty 方法（注册用的类模板没有源对象，不暴露属性）
 	fmt.Fprintf(b, "func (s *%sClass) GetProperty(name string) (data.Property, bool) {\n", typeName)
+	fmt.Fprintf(b, "\tif s.source == nil {\n\t\treturn nil, false\n\t}\n")
 	fmt.Fprintf(b, "\tswitch name {\n")
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		fieldName := field.Name
-
-		// 跳过小写开头的私有字段
-		if !IsExportedType(fieldName) {
-			continue
-		}
-
-		fmt.Fprintf(b, "\tcase \"%s\":\n", fieldName)
-
-		// 根据字段类型生成不同的属性值
-		if config != nil && isBlacklistedType(field.Type, config) {
-			// 黑名单类型使用 AnyValue
-			fmt.Fprintf(b, "\t\treturn node.NewProperty(nil, \"%s\", \"public\", true, data.NewAnyValue(s.source.%s)), true\n",
-				fieldName, fieldName)
-		} else if isStructType(field.Type) {
-			// 为避免引用未生成的 Class，这里统一回退 AnyValue
-			fmt.Fprintf(b, "\t\treturn node.NewProperty(nil, \"%s\", \"public\", true, data.NewAnyValue(s.source.%s)), true\n",
-				fieldName, fieldName)
-		} else {
-			fmt.Fprintf(b, "\t\treturn node.NewProperty(nil, \"%s\", \"public\", true, data.NewAnyValue(s.source.%s)), true\n",
-				fieldName, fieldName)
+	for _, field := range fields {
+		valueExpr := returnValueExpr(field.Type, "s.source."+field.Name, srcPkgPath, config, fileCache)
+		fmt.Fprintf(b, "\tcase %q:\n", field.Name)
+		fmt.Fprintf(b, "\t\treturn utils.NewProperty(name, func(ctx data.Context) data.Value {\n")
+		for _, guard := range field.nilGuards {
+			fmt.Fprintf(b, "\t\t\tif %s == nil {\n\t\t\t\treturn data.NewNullValue()\n\t\t\t}\n", guard)
 		}
+		fmt.Fprintf(b, "\t\t\treturn %s\n", valueExpr)
+		fmt.Fprintf(b, "\t\t}), true\n")
 	}
 	fmt.Fprintf(b, "\t}\n")
 	fmt.Fprintf(b, "\treturn nil, false\n")
@@ -290,36 +312,111 @@ func writePropertyMethods(b *strings.Builder, typeName string, structType reflec
 
 	// GetProperties 方法
 	fmt.Fprintf(b, "func (s *%sClass) GetProperties() map[string]data.Property {\n", typeName)
-	fmt.Fprintf(b, "\treturn map[string]data.Property{\n")
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		fieldName := field.Name
-
-		// 跳过小写开头的私有字段
-		if !IsExportedType(fieldName) {
-			continue
-		}
-
-		if config != nil && isBlacklistedType(field.Type, config) {
-			// 黑名单类型使用 AnyValue
-			fmt.Fprintf(b, "\t\t\"%s\": node.NewProperty(nil, \"%s\", \"public\", true, data.NewAnyValue(nil)),\n",
-				fieldName, fieldName)
-		} else if isStructType(field.Type) {
-			// 为避免引用未生成的 Class，这里统一回退 AnyValue
-			fmt.Fprintf(b, "\t\t\"%s\": node.NewProperty(nil, \"%s\", \"public\", true, data.NewAnyValue(nil)),\n",
-				fieldName, fieldName)
-		} else {
-			fmt.Fprintf(b, "\t\t\"%s\": node.NewProperty(nil, \"%s\", \"public\", true, data.NewAnyValue(nil)),\n",
-				fieldName, fieldName)
+	fmt.Fprintf(b, "\tproperties := make(map[string]data.Property)\n")
+	fmt.Fprintf(b, "\tfor _, name := range []string{")
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteString(", ")
 		}
+		fmt.Fprintf(b, "%q", field.Name)
 	}
+	fmt.Fprintf(b, "} {\n")
+	fmt.Fprintf(b, "\t\tif property, ok := s.GetProperty(name); ok {\n")
+	fmt.Fprintf(b, "\t\t\tproperties[name] = property\n")
+	fmt.Fprintf(b, "\t\t}\n")
 	fmt.Fprintf(b, "\t}\n")
+	fmt.Fprintf(b, "\treturn properties\n")
 	fmt.Fprintf(b, "}\n\n")
 
 	// SetProperty 方法
 	writeSetPropertyMethod(b, typeName, structType, importAlias, config, fileCache)
 }
 
+// classField 类属性对应的 Go 字段，含嵌入结构体提升上来的字段
+type classField struct {
+	reflect.StructField
+	// nilGuards 访问路径上经过的指针嵌入字段，如 s.source.Base，读写前需判空
+	nilGuards []string
+}
+
+// collectClassFields 收集类属性：导出字段按声明顺序排列，嵌入结构体的字段提升到外层
+// 嵌入结构体本身不再作为属性；经过未导出指针嵌入的字段无法判空、类型无法导入的字段无法引用，均跳过
+func collectClassFields(structType reflect.Type) []classField {
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []classField
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || isEmbeddedStruct(field) {
+			continue
+		}
+		if bad, _ := unimportableType(field.Type); bad != nil {
+			continue
+		}
+
+		var guards []string
+		selector := "s.source"
+		usable := true
+		for depth := 1; depth < len(field.Index); depth++ {
+			embedded := structType.FieldByIndex(field.Index[:depth])
+			selector += "." + embedded.Name
+			if embedded.Type.Kind() == reflect.Ptr {
+				if !embedded.IsExported() {
+					usable = false
+					break
+				}
+				guards = append(guards, selector)
+			}
+		}
+		if usable {
+			fields = append(fields, classField{StructField: field, nilGuards: guards})
+		}
+	}
+	return fields
+}
+
+// isEmbeddedStruct 判断字段是否为嵌入的结构体（或结构体指针）
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// embeddedParentType 返回作为父类的嵌入结构体：首个已生成类的嵌入结构体，没有时返回 nil
+func embeddedParentType(structType reflect.Type, config *Config) reflect.Type {
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !isEmbeddedStruct(field) {
+			continue
+		}
+		t := field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if isGeneratedClassType(t, config) {
+			return t
+		}
+	}
+	return nil
+}
+
+// classReflectType 返回类源对象的 reflect.Type 表达式：结构体取指针类型（方法集含指针接收者方法）
+func classReflectType(structType reflect.Type, importAlias string, fileCache *FileCache) string {
+	if structType.Kind() == reflect.Interface {
+		return fmt.Sprintf("reflect.TypeFor[%s.%s]()", importAlias, genericTypeName(structType, fileCache))
+	}
+	return fmt.Sprintf("reflect.TypeFor[*%s.%s]()", importAlias, genericTypeName(structType, fileCache))
+}
+
 // getStructTypeName 获取结构体类型名称
 func getStructTypeName(t reflect.Type) string {
 	for t.Kind() == reflect.Ptr {
@@ -361,7 +458,8 @@ func writeSetPropertyMethod(b *strings.Builder, typeName string, structType refl
 	fmt.Fprintf(b, "\t\treturn data.NewErrorThrow(nil, errors.New(\"无法设置属性，source 为 nil\"))\n")
 	fmt.Fprintf(b, "\t}\n\n")
 
-	if structType == nil || structType.Kind() != reflect.Struct || structType.NumField() == 0 {
+	fields := collectClassFields(structType)
+	if len(fields) == 0 {
 		// 无字段时返回属性不存在错误
 		fmt.Fprintf(b, "\treturn data.NewErrorThrow(nil, errors.New(\"属性不存在: \" + name))\n")
 		fmt.Fprintf(b, "}\n\n")
@@ -369,16 +467,15 @@ func writeSetPropertyMethod(b *strings.Builder, typeName string, structType refl
 	}
 
 	fmt.Fprintf(b, "\tswitch name {\n")
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
+	for _, field := range fields {
 		fieldName := field.Name
 
-		// 跳过小写开头的私有字段
-		if !IsExportedType(fieldName) {
-			continue
-		}
-
 		fmt.Fprintf(b, "\tcase \"%s\":\n", fieldName)
+		for _, guard := range field.nilGuards {
+			fmt.Fprintf(b, "\t\tif %s == nil {\n", guard)
+			fmt.Fprintf(b, "\t\t\treturn data.NewErrorThrow(nil, errors.New(\"无法设置属性，%s 为 nil\"))\n", strings.TrimPrefix(guard, "s.source."))
+			fmt.Fprintf(b, "\t\t}\n")
+		}
 
 		// 获取字段类型
 		fieldType := field.Type
@@ -426,6 +523,11 @@ func MarkTypePackageUsed(t reflect.Type, fileCache *FileCache) {
 		t = t.Elem()
 	}
 
+	// 具名的切片、map 等类型（如 asn1.ObjectIdentifier）按类型名引用
+	if t.Name() != "" && t.PkgPath() != "" {
+		fileCache.MarkImportUsed(t.PkgPath())
+	}
+
 	// 处理复合类型
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array:
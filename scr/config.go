@@ -2,24 +2,39 @@ package scr
 
 type Config struct {
 	// 输出根目录，例如: origami
-	OutputRoot string
-	// 自定义 GetName 拼接前缀；为空则使用源包名
-	NamePrefix string
+	OutputRoot string `json:"output_root" yaml:"output_root" toml:"output_root"`
+	// 自定义 GetName 拼接前缀，必填（Validate 拒绝空值）
+	NamePrefix string `json:"name_prefix" yaml:"name_prefix" toml:"name_prefix"`
 	// 最大递归生成层次（<=0 表示不限制）
-	MaxDepth int
+	MaxDepth int `json:"max_depth" yaml:"max_depth" toml:"max_depth"`
+	// 并行生成的 worker 数（<=0 表示使用 GOMAXPROCS）
+	Workers int `json:"workers" yaml:"workers" toml:"workers"`
+
+	// 数值字符串严格模式：开启后生成的 load.go 在 Load 中调用 utils.SetStrictNumericStrings(true)，
+	// 脚本传入的字符串不再自动解析为数值参数。严格模式对整个进程生效，加载任一开启的子包即生效
+	StrictNumericStrings bool `json:"strict_numeric_strings" yaml:"strict_numeric_strings" toml:"strict_numeric_strings"`
 
 	// 黑名单配置
-	Blacklist BlacklistConfig
+	Blacklist BlacklistConfig `json:"blacklist" yaml:"blacklist" toml:"blacklist"`
+
+	// 依赖包映射配置：导入路径 -> 输出子包名（同时作为脚本命名空间）
+	// 例如: github.com/redis/go-redis/v9 -> redis
+	PackageMappings map[string]string `json:"package_mappings" yaml:"package_mappings" toml:"package_mappings"`
 
-	// 依赖包映射配置
-	PackageMappings map[string]string
+	// 泛型实例化配置：导入路径 -> 实例化表达式列表
+	// 例如: github.com/php-any/generator/demo -> [Cache[string,*User], Map[int,string]]
+	// 类型实参可使用内置类型、该包内的类型及该包导入的包（如 time.Duration）
+	Instantiations map[string][]string `json:"instantiations" yaml:"instantiations" toml:"instantiations"`
 
 	// 文件固定替换，准备生成的文件时检查，如果匹配则替换而不是新生成
-	FixedReplace map[string]string
+	FixedReplace map[string]string `json:"fixed_replace" yaml:"fixed_replace" toml:"fixed_replace"`
+
+	// 输出后端：为空时写入磁盘；可替换为 MemoryFS、DryRunFS
+	Output OutputFS `json:"-" yaml:"-" toml:"-"`
 }
 
 // BlacklistConfig 黑名单配置
 type BlacklistConfig struct {
 	// 包路径黑名单-只能生成 data.AnyValue
-	Packages []string
+	Packages []string `json:"packages" yaml:"packages" toml:"packages"`
 }
@@ -0,0 +1,222 @@
+package scr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile 配置文件模块，支持 YAML / JSON / TOML，并在生成前校验
+
+// ConfigError 配置校验错误，汇总全部问题一次性报告
+type ConfigError struct {
+	// 配置来源（文件路径），为空表示代码构造的配置
+	Source   string
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	b := &strings.Builder{}
+	if e.Source != "" {
+		fmt.Fprintf(b, "配置文件 %s 校验失败:", e.Source)
+	} else {
+		b.WriteString("配置校验失败:")
+	}
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// LoadConfig 从配置文件加载 Config，按扩展名选择格式（.yaml/.yml/.json/.toml），并执行校验
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	var problems []string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		problems, err = decodeYAMLConfig(raw, config)
+	case ".json":
+		problems, err = decodeJSONConfig(raw, config)
+	case ".toml":
+		problems, err = decodeTOMLConfig(raw, config)
+	default:
+		return nil, fmt.Errorf("不支持的配置文件格式: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+
+	problems = append(problems, config.validationProblems()...)
+	if len(problems) > 0 {
+		return nil, &ConfigError{Source: path, Problems: problems}
+	}
+	return config, nil
+}
+
+// decodeYAMLConfig 解析 YAML，未知键作为校验问题返回
+func decodeYAMLConfig(raw []byte, config *Config) ([]string, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(config); err != nil {
+		if typeErr, ok := err.(*yaml.TypeError); ok {
+			return typeErr.Errors, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// decodeJSONConfig 解析 JSON，未知键作为校验问题返回（全部报告，而不是只报告第一个）
+func decodeJSONConfig(raw []byte, config *Config) ([]string, error) {
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+	var problems []string
+	for _, key := range unknownJSONKeys(raw, reflect.TypeOf(config).Elem(), "") {
+		problems = append(problems, "未知配置项: "+key)
+	}
+	return problems, nil
+}
+
+// unknownJSONKeys 返回 JSON 对象中不对应结构体 t 字段的键（按名称排序），嵌套结构体的键以 . 连接
+// 与 encoding/json 一致，字段名匹配不区分大小写
+func unknownJSONKeys(raw []byte, t reflect.Type, prefix string) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		// 非对象的值已由 Unmarshal 报告类型错误
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+
+	var unknown []string
+	for _, key := range sortedKeys(object) {
+		fieldType, ok := fields[strings.ToLower(key)]
+		if !ok {
+			unknown = append(unknown, prefix+key)
+			continue
+		}
+		if fieldType.Kind() == reflect.Struct {
+			unknown = append(unknown, unknownJSONKeys(object[key], fieldType, prefix+key+".")...)
+		}
+	}
+	return unknown
+}
+
+// decodeTOMLConfig 解析 TOML，未知键作为校验问题返回
+func decodeTOMLConfig(raw []byte, config *Config) ([]string, error) {
+	meta, err := toml.Decode(string(raw), config)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	for _, key := range meta.Undecoded() {
+		problems = append(problems, fmt.Sprintf("未知配置项: %s", key.String()))
+	}
+	return problems, nil
+}
+
+// Validate 校验配置，汇总全部问题后返回 *ConfigError
+func (c *Config) Validate() error {
+	if problems := c.validationProblems(); len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// validationProblems 收集配置中的全部问题
+func (c *Config) validationProblems() []string {
+	var problems []string
+
+	if strings.TrimSpace(c.OutputRoot) == "" {
+		problems = append(problems, "output_root 不能为空")
+	}
+	if strings.TrimSpace(c.NamePrefix) == "" {
+		problems = append(problems, "name_prefix 不能为空")
+	}
+
+	// 按目标排序，保证报告顺序稳定
+	for _, target := range sortedKeys(c.FixedReplace) {
+		source := c.FixedReplace[target]
+		if _, err := os.Stat(source); err != nil {
+			problems = append(problems, fmt.Sprintf("fixed_replace[%s] 的替换文件不存在: %s", target, source))
+		}
+	}
+
+	problems = append(problems, c.mappingProblems()...)
+	problems = append(problems, c.instantiationProblems()...)
+	return problems
+}
+
+// instantiationProblems 检查泛型实例化表达式的语法；符号与约束在加载包后由 resolveInstances 校验
+func (c *Config) instantiationProblems() []string {
+	var problems []string
+	for _, importPath := range sortedKeys(c.Instantiations) {
+		for _, expr := range c.Instantiations[importPath] {
+			if _, _, err := parseInstanceExpr(expr); err != nil {
+				problems = append(problems, fmt.Sprintf("instantiations[%s]: %v", importPath, err))
+			}
+		}
+	}
+	return problems
+}
+
+// mappingProblems 检查包映射：值必须是合法包名，且不同包不能映射到同一输出子包
+func (c *Config) mappingProblems() []string {
+	var problems []string
+
+	owners := map[string]string{}
+	for _, importPath := range sortedKeys(c.PackageMappings) {
+		name := c.PackageMappings[importPath]
+		if strings.TrimSpace(name) == "" {
+			problems = append(problems, fmt.Sprintf("package_mappings[%s] 不能为空", importPath))
+			continue
+		}
+		if !token.IsIdentifier(name) {
+			problems = append(problems, fmt.Sprintf("package_mappings[%s] 不是合法的 Go 包名: %s", importPath, name))
+			continue
+		}
+		if owner, ok := owners[name]; ok {
+			problems = append(problems, fmt.Sprintf("package_mappings 输出目录重叠: %s 与 %s 都映射到 %s", owner, importPath, filepath.Join(c.OutputRoot, name)))
+			continue
+		}
+		owners[name] = importPath
+	}
+	return problems
+}
+
+// sortedKeys 返回排序后的 map 键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
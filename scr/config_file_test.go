@@ -0,0 +1,125 @@
+package scr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "replace.go")
+	if err := os.WriteFile(existing, []byte("package demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   Config
+		problems []string
+	}{
+		{
+			name:   "有效配置",
+			config: Config{OutputRoot: "out", NamePrefix: "demo"},
+		},
+		{
+			name:     "输出目录与前缀为空",
+			config:   Config{OutputRoot: " ", NamePrefix: ""},
+			problems: []string{"output_root 不能为空", "name_prefix 不能为空"},
+		},
+		{
+			name: "替换文件存在",
+			config: Config{OutputRoot: "out", NamePrefix: "demo", FixedReplace: map[string]string{
+				"out/demo/user_class.go": existing,
+			}},
+		},
+		{
+			name: "替换文件不存在",
+			config: Config{OutputRoot: "out", NamePrefix: "demo", FixedReplace: map[string]string{
+				"out/demo/user_class.go": "missing.go",
+			}},
+			problems: []string{"fixed_replace[out/demo/user_class.go] 的替换文件不存在: missing.go"},
+		},
+		{
+			name: "包映射为空或不是合法包名",
+			config: Config{OutputRoot: "out", NamePrefix: "demo", PackageMappings: map[string]string{
+				"example.com/a": "",
+				"example.com/b": "go-redis",
+			}},
+			problems: []string{
+				"package_mappings[example.com/a] 不能为空",
+				"package_mappings[example.com/b] 不是合法的 Go 包名: go-redis",
+			},
+		},
+		{
+			name: "不同包映射到同一输出子包",
+			config: Config{OutputRoot: "out", NamePrefix: "demo", PackageMappings: map[string]string{
+				"example.com/a/redis": "redis",
+				"example.com/b/redis": "redis",
+			}},
+			problems: []string{"package_mappings 输出目录重叠: example.com/a/redis 与 example.com/b/redis 都映射到 " + filepath.Join("out", "redis")},
+		},
+		{
+			name: "实例化语法错误",
+			config: Config{OutputRoot: "out", NamePrefix: "demo", Instantiations: map[string][]string{
+				"example.com/demo": {"Cache[string,*User]", "Cache"},
+			}},
+			problems: []string{"instantiations[example.com/demo]: 实例化 Cache 不是 名称[类型实参] 形式"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, 期望通过", err)
+				}
+				return
+			}
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Validate() = %v, 期望 *ConfigError", err)
+			}
+			if !reflect.DeepEqual(configErr.Problems, tt.problems) {
+				t.Errorf("Problems = %q, 期望 %q", configErr.Problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUnknownKey(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"gen.yaml", "output_root: out\nname_prefix: demo\nunknown: 1\nother: 2\nblacklist:\n  nested: 3\n"},
+		{"gen.json", `{"output_root": "out", "name_prefix": "demo", "unknown": 1, "other": 2, "blacklist": {"nested": 3}}`},
+		{"gen.toml", "output_root = \"out\"\nname_prefix = \"demo\"\nunknown = 1\nother = 2\n[blacklist]\nnested = 3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadConfig(path)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("LoadConfig() = %v, 期望 *ConfigError", err)
+			}
+			// 每个未知键各报告一次
+			if len(configErr.Problems) != 3 {
+				t.Fatalf("Problems = %q, 期望 3 个未知配置项", configErr.Problems)
+			}
+			for _, key := range []string{"unknown", "other", "nested"} {
+				if !strings.Contains(strings.Join(configErr.Problems, "\n"), key) {
+					t.Errorf("Problems = %q, 缺少未知配置项 %s", configErr.Problems, key)
+				}
+			}
+		})
+	}
+}
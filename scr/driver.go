@@ -0,0 +1,268 @@
+package scr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Driver 驱动程序模块
+//
+// 生成流程依赖 reflect.Type 与函数值，源码分析得到的符号无法直接进入流程。
+// 这里为目标包生成一个临时 main 程序，由它引用全部符号并调用 GenerateAll，
+// 再在当前模块中 go run 该程序。多个包在同一个驱动程序中一起生成，
+// 共享注册表，依赖的同一输出子包只写出一份完整的 load.go。
+// 驱动程序总是生成到内存，并把文件与报告回传，由调用方按 Config.Output 写出。
+
+// driverImportAlias 驱动程序中目标包的导入别名，第二个起追加序号（target2、target3…）
+const driverImportAlias = "target"
+
+// DriverResult 驱动程序回传给调用方的生成结果
+type DriverResult struct {
+	// 生成的文件：路径 -> 内容
+	Files map[string][]byte
+	// 生成报告
+	Report *Report
+}
+
+// GenerateFromPackage 分析导入路径对应的包，并为其全部导出符号生成绑定
+func GenerateFromPackage(importPath string, config *Config) (*Report, error) {
+	return GenerateFromPackages([]string{importPath}, config)
+}
+
+// GenerateFromPackages 分析多个导入路径对应的包，在一次驱动程序运行中为其全部导出符号生成绑定
+func GenerateFromPackages(importPaths []string, config *Config) (*Report, error) {
+	if len(importPaths) == 0 {
+		return nil, errors.New("缺少导入路径")
+	}
+	infos, err := LoadPackages(importPaths)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if info.Instances, err = resolveInstances(info.types, config.Instantiations[info.Path]); err != nil {
+			return nil, err
+		}
+		if len(info.Symbols) == 0 && len(info.Instances) == 0 {
+			return nil, fmt.Errorf("包 %s 中没有可生成的导出符号", info.Path)
+		}
+	}
+	result, err := runDriver(infos, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFiles(outputFS(config), result.Files); err != nil {
+		return nil, err
+	}
+	if result.Report == nil {
+		result.Report = &Report{}
+	}
+	return result.Report, nil
+}
+
+// runDriver 写出临时驱动程序并在当前模块中运行，返回其回传的生成结果
+func runDriver(infos []*PackageInfo, config *Config) (*DriverResult, error) {
+	moduleRoot, err := findModuleRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	// 驱动目录必须位于模块内部，才能解析目标包与 scr 包
+	dir, err := os.MkdirTemp(moduleRoot, ".origami-gen-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	src, err := buildDriverSource(infos)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0644); err != nil {
+		return nil, err
+	}
+
+	configFile := filepath.Join(dir, "config.json")
+	configData, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(configFile, configData, 0644); err != nil {
+		return nil, err
+	}
+
+	// 保持当前工作目录，相对路径（OutputRoot、FixedReplace）与直接调用时一致
+	resultFile := filepath.Join(dir, "result.json")
+	paths := make([]string, len(infos))
+	for i, info := range infos {
+		paths[i] = info.Path
+	}
+	label := strings.Join(paths, ", ")
+	cmd := exec.Command("go", "run", dir, configFile, resultFile)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("运行驱动程序失败 (%s): %w", label, err)
+	}
+
+	resultData, err := os.ReadFile(resultFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取驱动程序结果失败 (%s): %w", label, err)
+	}
+	result := &DriverResult{}
+	if err := json.Unmarshal(resultData, result); err != nil {
+		return nil, fmt.Errorf("解析驱动程序结果失败 (%s): %w", label, err)
+	}
+	return result, nil
+}
+
+// findModuleRoot 查找当前工作目录所属模块的根目录
+func findModuleRoot() (string, error) {
+	out, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		return "", fmt.Errorf("执行 go env GOMOD 失败: %w", err)
+	}
+	gomod := strings.TrimSpace(string(out))
+	if gomod == "" || gomod == os.DevNull {
+		return "", errors.New("当前目录不在 Go 模块中，无法运行驱动程序")
+	}
+	return filepath.Dir(gomod), nil
+}
+
+// buildDriverSource 构建驱动程序源码
+func buildDriverSource(infos []*PackageInfo) ([]byte, error) {
+	b := &bytes.Buffer{}
+
+	// 目标包先占用 target、target2… 别名，类型实参引用目标包时沿用
+	deps := &driverImports{aliases: map[string]string{}}
+	for i, info := range infos {
+		alias := driverImportAlias
+		if i > 0 {
+			alias = fmt.Sprintf("%s%d", driverImportAlias, i+1)
+		}
+		deps.aliases[info.Path] = alias
+	}
+
+	// 泛型实例化先生成表达式，以便收集类型实参引用的包
+	var symbolExprs []string
+	hasGenericFunc := false
+	for _, info := range infos {
+		alias := deps.aliases[info.Path]
+		for _, sym := range info.Symbols {
+			symbolExprs = append(symbolExprs, driverSymbolExpr(sym, alias))
+		}
+		for _, inst := range info.Instances {
+			symbolExprs = append(symbolExprs, driverInstanceExpr(inst, info.Path, deps))
+			hasGenericFunc = hasGenericFunc || inst.Kind == SymbolFunc
+		}
+	}
+
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n")
+	b.WriteString("\t\"encoding/json\"\n")
+	b.WriteString("\t\"fmt\"\n")
+	b.WriteString("\t\"os\"\n")
+	if hasGenericFunc {
+		b.WriteString("\t\"reflect\"\n")
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(b, "\t%q\n", scrPackagePath())
+	for _, pkgPath := range sortedKeys(deps.aliases) {
+		fmt.Fprintf(b, "\t%s %q\n", deps.aliases[pkgPath], pkgPath)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("var genList = []any{\n")
+	for _, expr := range symbolExprs {
+		fmt.Fprintf(b, "\t%s,\n", expr)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("func main() {\n")
+	b.WriteString("\traw, err := os.ReadFile(os.Args[1])\n")
+	b.WriteString("\tif err != nil {\n\t\tfmt.Println(err)\n\t\tos.Exit(1)\n\t}\n")
+	b.WriteString("\tvar config scr.Config\n")
+	b.WriteString("\tif err := json.Unmarshal(raw, &config); err != nil {\n\t\tfmt.Println(err)\n\t\tos.Exit(1)\n\t}\n")
+	b.WriteString("\tout := scr.NewMemoryFS()\n")
+	b.WriteString("\tconfig.Output = out\n")
+	b.WriteString("\treport, err := scr.GenerateAll(genList, &config)\n")
+	b.WriteString("\tif err != nil {\n\t\tfmt.Println(err)\n\t\tos.Exit(1)\n\t}\n")
+	b.WriteString("\tresult, err := json.Marshal(scr.DriverResult{Files: out.Files(), Report: report})\n")
+	b.WriteString("\tif err != nil {\n\t\tfmt.Println(err)\n\t\tos.Exit(1)\n\t}\n")
+	b.WriteString("\tif err := os.WriteFile(os.Args[2], result, 0644); err != nil {\n\t\tfmt.Println(err)\n\t\tos.Exit(1)\n\t}\n")
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// driverSymbolExpr 返回驱动程序中引用符号的表达式
+// - 函数：直接取函数值
+// - 结构体：*T 空指针
+// - 接口：*I 空指针，由 generateFromType 解引用后按接口生成
+func driverSymbolExpr(sym PackageSymbol, alias string) string {
+	switch sym.Kind {
+	case SymbolFunc:
+		return alias + "." + sym.Name
+	default:
+		return "(*" + alias + "." + sym.Name + ")(nil)"
+	}
+}
+
+// driverInstanceExpr 返回驱动程序中引用泛型实例的表达式
+// - 泛型函数：scr.GenericFunc{...}，附带类型实参
+// - 泛型结构体/接口：*T[...] 空指针
+func driverInstanceExpr(inst PackageInstance, targetPath string, deps *driverImports) string {
+	qualifier := func(p *types.Package) string {
+		return deps.alias(p)
+	}
+	args := make([]string, len(inst.TypeArgs))
+	for i, arg := range inst.TypeArgs {
+		args[i] = types.TypeString(arg, qualifier)
+	}
+	instance := fmt.Sprintf("%s.%s[%s]", deps.aliases[targetPath], inst.Name, strings.Join(args, ", "))
+
+	if inst.Kind != SymbolFunc {
+		return "(*" + instance + ")(nil)"
+	}
+	typeArgs := make([]string, len(args))
+	for i, arg := range args {
+		typeArgs[i] = fmt.Sprintf("reflect.TypeFor[%s]()", arg)
+	}
+	return fmt.Sprintf("scr.GenericFunc{Name: %q, PkgPath: %q, Value: %s, TypeArgs: []reflect.Type{%s}}",
+		inst.Name, targetPath, instance, strings.Join(typeArgs, ", "))
+}
+
+// driverImports 驱动程序导入的目标包与类型实参引用的其他包：导入路径 -> 别名
+type driverImports struct {
+	aliases map[string]string
+}
+
+// alias 返回包的导入别名，与驱动程序已有导入或其他包重名时追加序号
+func (d *driverImports) alias(p *types.Package) string {
+	if alias, ok := d.aliases[p.Path()]; ok {
+		return alias
+	}
+	taken := map[string]bool{"json": true, "fmt": true, "os": true, "reflect": true, "scr": true, driverImportAlias: true}
+	for _, alias := range d.aliases {
+		taken[alias] = true
+	}
+	alias := p.Name()
+	for i := 2; taken[alias]; i++ {
+		alias = fmt.Sprintf("%s%d", p.Name(), i)
+	}
+	d.aliases[p.Path()] = alias
+	return alias
+}
+
+// scrPackagePath 返回本包的导入路径，避免在驱动程序中写死
+func scrPackagePath() string {
+	return reflect.TypeOf(Config{}).PkgPath()
+}
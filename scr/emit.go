@@ -2,16 +2,37 @@ package scr
 
 import (
 	"bytes"
+	"fmt"
 	"go/format"
-	"os"
 	"path/filepath"
 )
 
 // Emit 文件输出模块
 
-// emitFile 生成文件，自动 gofmt
-func emitFile(targetPath string, pkg string, body string) error {
+// GeneratedMarker 生成文件首行标记，遵循 Go 的生成代码约定
+// 只有带此标记的文件才被视为生成器所有
+const GeneratedMarker = "// Code generated by origami-gen. DO NOT EDIT."
+
+// isGeneratedFile 判断文件内容是否带有生成标记（标记须位于 package 子句之前）
+func isGeneratedFile(data []byte) bool {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if string(line) == GeneratedMarker {
+			return true
+		}
+		if bytes.HasPrefix(line, []byte("package ")) {
+			return false
+		}
+	}
+	return false
+}
+
+// emitFile 生成文件，自动 gofmt，经由配置的输出后端写出
+// 格式化失败说明生成的源码有语法错误，返回 error 而不写出文件
+func emitFile(targetPath string, pkg string, body string, cache *GroupCache) error {
 	var buf bytes.Buffer
+	buf.WriteString(GeneratedMarker)
+	buf.WriteString("\n\n")
 	buf.WriteString("package ")
 	buf.WriteString(pkg)
 	buf.WriteString("\n\n")
@@ -19,37 +40,60 @@ func emitFile(targetPath string, pkg string, body string) error {
 
 	formatted, err := format.Source(buf.Bytes())
 	if err != nil {
-		// 回退到未格式化内容，便于排错
-		formatted = buf.Bytes()
+		return fmt.Errorf("格式化 %s 失败: %w", targetPath, err)
 	}
 
-	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-		return err
-	}
-	return os.WriteFile(targetPath, formatted, 0644)
+	return cache.writeOutput(targetPath, formatted)
 }
 
 // emitLoadFile 生成 load.go 文件
-func emitLoadFile(pkgName string, cache *GroupCache) error {
-	outDir := filepath.Join(cache.Config.OutputRoot, pkgName)
-	loadFile := filepath.Join(outDir, "load.go")
+// 注册本次生成的类与函数；清单中其他请求的段仍保留的本子包文件（owned 之外的段）一并注册，
+// 单独生成其他包时不会丢失依赖子包的注册，已不再生成的文件则不再注册
+func emitLoadFile(pkgName string, owned map[string]bool, cache *GroupCache) error {
+	loadFile := filepath.Join(cache.Config.OutputRoot, pkgName, "load.go")
 
 	// 使用注册表统一生成
 	classes, functions := globalCache.ListRegistered(pkgName)
-	body := buildLoadFileBody(pkgName, classes, functions)
+	carriedClasses, carriedFunctions, err := carriedRegistrations(cache.Config, pkgName, owned)
+	if err != nil {
+		return err
+	}
+	classes = mergeNames(classes, carriedClasses)
+	functions = mergeNames(functions, carriedFunctions)
+	body := buildLoadFileBody(pkgName, classes, functions, cache.Config.StrictNumericStrings)
 
-	return emitFile(loadFile, pkgName, body)
+	return emitFile(loadFile, pkgName, body, cache)
+}
+
+// mergeNames 合并两组名称，去重后排序
+func mergeNames(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, name := range a {
+		set[name] = true
+	}
+	for _, name := range b {
+		set[name] = true
+	}
+	return sortedKeys(set)
 }
 
 // buildLoadFileBody 构建 load.go 文件内容
-func buildLoadFileBody(pkgName string, classes, functions []string) string {
+// strict 为 true 时 Load 先开启数值字符串严格模式
+func buildLoadFileBody(pkgName string, classes, functions []string, strict bool) string {
 	b := &bytes.Buffer{}
 
 	b.WriteString("import (\n")
+	if strict {
+		b.WriteString("\t\"github.com/php-any/generator/utils\"\n")
+	}
 	b.WriteString("\t\"github.com/php-any/origami/data\"\n")
 	b.WriteString(")\n\n")
 
 	b.WriteString("func Load(vm data.VM) {\n")
+	if strict {
+		b.WriteString("\t// 配置开启了数值字符串严格模式\n")
+		b.WriteString("\tutils.SetStrictNumericStrings(true)\n\n")
+	}
 
 	// 添加函数
 	if len(functions) > 0 {
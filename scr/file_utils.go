@@ -2,9 +2,13 @@ package scr
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"unicode"
+
+	"golang.org/x/mod/modfile"
 )
 
 // FileUtils 文件工具模块，负责文件名生成和文件操作
@@ -33,13 +37,13 @@ func generateExpectedFileName(t reflect.Type, cache *GroupCache) string {
 	switch t.Kind() {
 	case reflect.Struct:
 		// 结构体类型：直接获取包路径和类型名
-		pkgName = pkgBaseName(t.PkgPath())
-		typeName = t.Name()
+		pkgName = outputPackageName(t.PkgPath(), cache.Config)
+		typeName = typeIdentName(t)
 	case reflect.Ptr:
 		// 指针类型：检查是否指向结构体
 		if t.Elem() != nil && t.Elem().Kind() == reflect.Struct {
-			pkgName = pkgBaseName(t.Elem().PkgPath())
-			typeName = t.Elem().Name()
+			pkgName = outputPackageName(t.Elem().PkgPath(), cache.Config)
+			typeName = typeIdentName(t.Elem())
 		}
 	case reflect.Func:
 		// 函数类型：根据函数签名生成文件名
@@ -47,10 +51,10 @@ func generateExpectedFileName(t reflect.Type, cache *GroupCache) string {
 		if t.NumOut() > 0 {
 			returnType := t.Out(0)
 			if returnType.Kind() == reflect.Ptr && returnType.Elem().Kind() == reflect.Struct {
-				pkgName = pkgBaseName(returnType.Elem().PkgPath())
+				pkgName = outputPackageName(returnType.Elem().PkgPath(), cache.Config)
 				typeName = returnType.Elem().Name()
 			} else if returnType.Kind() == reflect.Struct {
-				pkgName = pkgBaseName(returnType.PkgPath())
+				pkgName = outputPackageName(returnType.PkgPath(), cache.Config)
 				typeName = returnType.Name()
 			}
 		}
@@ -60,10 +64,10 @@ func generateExpectedFileName(t reflect.Type, cache *GroupCache) string {
 			if t.NumIn() > 0 {
 				paramType := t.In(0)
 				if paramType.Kind() == reflect.Ptr && paramType.Elem().Kind() == reflect.Struct {
-					pkgName = pkgBaseName(paramType.Elem().PkgPath())
+					pkgName = outputPackageName(paramType.Elem().PkgPath(), cache.Config)
 					typeName = paramType.Elem().Name()
 				} else if paramType.Kind() == reflect.Struct {
-					pkgName = pkgBaseName(paramType.PkgPath())
+					pkgName = outputPackageName(paramType.PkgPath(), cache.Config)
 					typeName = paramType.Name()
 				}
 			}
@@ -114,10 +118,10 @@ func generateExpectedFileName(t reflect.Type, cache *GroupCache) string {
 			// 尝试从参数或返回值中获取包名
 			if t.NumIn() > 0 {
 				if pkgPath := t.In(0).PkgPath(); pkgPath != "" {
-					pkgName = pkgBaseName(pkgPath)
+					pkgName = outputPackageName(pkgPath, cache.Config)
 				} else if t.In(0).Kind() == reflect.Ptr && t.In(0).Elem() != nil {
 					if pkgPath := t.In(0).Elem().PkgPath(); pkgPath != "" {
-						pkgName = pkgBaseName(pkgPath)
+						pkgName = outputPackageName(pkgPath, cache.Config)
 					}
 				}
 			}
@@ -125,10 +129,10 @@ func generateExpectedFileName(t reflect.Type, cache *GroupCache) string {
 			if t.NumOut() > 0 {
 				if pkgName == "" {
 					if pkgPath := t.Out(0).PkgPath(); pkgPath != "" {
-						pkgName = pkgBaseName(pkgPath)
+						pkgName = outputPackageName(pkgPath, cache.Config)
 					} else if t.Out(0).Kind() == reflect.Ptr && t.Out(0).Elem() != nil {
 						if pkgPath := t.Out(0).Elem().PkgPath(); pkgPath != "" {
-							pkgName = pkgBaseName(pkgPath)
+							pkgName = outputPackageName(pkgPath, cache.Config)
 						}
 					}
 				}
@@ -166,15 +170,15 @@ func generateExpectedFileName(t reflect.Type, cache *GroupCache) string {
 // 参数说明：
 // - src: 源文件路径
 // - dst: 目标文件路径
+// - cache: 生成缓存，目标文件经由其输出后端写出
 //
 // 返回值：
 // - 错误信息，成功时返回 nil
 //
 // 功能：
 // - 读取源文件内容
-// - 写入到目标文件
-// - 使用 0644 权限（用户读写，组和其他用户只读）
-func copyFile(src, dst string) error {
+// - 写入到目标文件（源文件始终从磁盘读取）
+func copyFile(src, dst string, cache *GroupCache) error {
 	// 读取源文件
 	data, err := os.ReadFile(src)
 	if err != nil {
@@ -182,7 +186,7 @@ func copyFile(src, dst string) error {
 	}
 
 	// 写入目标文件
-	return os.WriteFile(dst, data, 0644)
+	return cache.writeOutput(dst, data)
 }
 
 // pkgBaseName 从完整包路径提取最终包名
@@ -195,15 +199,95 @@ func copyFile(src, dst string) error {
 //
 // 处理规则：
 // - 空路径返回 "main"
-// - 使用 filepath.Base 提取最后一段
-// - 处理特殊情况（如 "." 或路径分隔符）
+// - 取最后一段；若最后一段是主版本号（如 /v9）则取上一段
+// - 去掉 gopkg.in 风格的 .vN 后缀，以及 go- 前缀、-go 后缀
+// - 去掉其余非标识符字符，例如 "github.com/redis/go-redis/v9" -> "redis"
 func pkgBaseName(pkgPath string) string {
 	if pkgPath == "" {
 		return "main"
 	}
-	base := filepath.Base(pkgPath)
-	if base == "." || base == string(filepath.Separator) {
+	parts := strings.Split(strings.Trim(pkgPath, "/"), "/")
+	base := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(base) {
+		base = parts[len(parts)-2]
+	}
+	if i := strings.LastIndex(base, "."); i > 0 && isMajorVersion(base[i+1:]) {
+		base = base[:i]
+	}
+	base = strings.TrimSuffix(strings.TrimPrefix(base, "go-"), "-go")
+
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, base)
+	if name == "" {
 		return pkgPath
 	}
-	return base
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "_" + name
+	}
+	return name
+}
+
+// isMajorVersion 判断路径段是否为主版本号，例如 v2、v9
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// outputPackageName 返回源包对应的输出子包名（同时是 OutputRoot 下的目录名）
+// 优先使用 Config.PackageMappings，否则按 pkgBaseName 推导
+func outputPackageName(pkgPath string, config *Config) string {
+	if config != nil {
+		if name, ok := config.PackageMappings[pkgPath]; ok && name != "" {
+			return name
+		}
+	}
+	return pkgBaseName(pkgPath)
+}
+
+// outputImportPath 返回输出子包的 Go 导入路径：向上查找 OutputRoot 所在模块的 go.mod，
+// 按模块路径与相对目录拼接；OutputRoot 不在任何模块中时返回空字符串
+func outputImportPath(pkgName string, config *Config) string {
+	dir, err := filepath.Abs(filepath.Join(config.OutputRoot, pkgName))
+	if err != nil {
+		return ""
+	}
+	for root := filepath.Dir(dir); ; root = filepath.Dir(root) {
+		if data, err := os.ReadFile(filepath.Join(root, "go.mod")); err == nil {
+			modPath := modfile.ModulePath(data)
+			rel, err := filepath.Rel(root, dir)
+			if modPath == "" || err != nil {
+				return ""
+			}
+			return path.Join(modPath, filepath.ToSlash(rel))
+		}
+		if parent := filepath.Dir(root); parent == root {
+			return ""
+		}
+	}
+}
+
+// scriptNamespace 返回源包在脚本中的命名空间（GetName 前缀）
+// 优先级：PackageMappings > NamePrefix > 输出子包名
+func scriptNamespace(pkgPath string, config *Config) string {
+	if config == nil {
+		return pkgBaseName(pkgPath)
+	}
+	if name, ok := config.PackageMappings[pkgPath]; ok && name != "" {
+		return name
+	}
+	if config.NamePrefix != "" {
+		return config.NamePrefix
+	}
+	return outputPackageName(pkgPath, config)
 }
@@ -7,7 +7,8 @@ import (
 )
 
 // buildFunctionFileBody 构建函数文件内容
-func buildFunctionFileBody(srcPkgPath, pkgName, namePrefix, funcName string, t reflect.Type, fileCache *FileCache, config *Config) string {
+// generic 为泛型函数实例（Name 为空表示普通函数），调用处需要带上类型实参
+func buildFunctionFileBody(srcPkgPath, pkgName, namePrefix, funcName string, t reflect.Type, result *resultClass, generic *GenericFunc, fileCache *FileCache, config *Config) string {
 	b := &strings.Builder{}
 	importAlias := pkgName + "src"
 
@@ -18,6 +19,16 @@ func buildFunctionFileBody(srcPkgPath, pkgName, namePrefix, funcName string, t r
 	// 收集导入
 	collectFunctionImportsToCache(srcPkgPath, pkgName, paramTypes, returnTypes, fileCache, config)
 
+	// 调用表达式；泛型函数实例按声明名查找源码信息
+	declName, callee := funcName, importAlias+"."+funcName
+	if generic != nil && generic.Name != "" {
+		declName, callee = generic.Name, generic.callee(importAlias, fileCache)
+	}
+
+	// 参数名：源码中声明的名称优先，启发式兜底；脚本中保持声明的名称，Go 局部变量冲突时追加后缀
+	sourceNames, _ := sourceParamIndex(srcPkgPath).funcSignature(declName)
+	scriptNames, paramNames := resolveParamNames(sourceNames.params, paramTypes, funcName, isVariadic, fileCache)
+
 	// 生成函数结构体
 	writeFunctionStruct(b, funcName, fileCache, srcPkgPath)
 
@@ -26,7 +37,7 @@ func buildFunctionFileBody(srcPkgPath, pkgName, namePrefix, funcName string, t r
 	if srcPkgPath != "" {
 		origPkgName = pkgBaseName(srcPkgPath)
 	}
-	writeFunctionImplementation(b, namePrefix, funcName, paramTypes, paramNames, returnTypes, importAlias, fileCache, isVariadic, variadicElem, origPkgName)
+	writeFunctionImplementation(b, namePrefix, funcName, callee, paramTypes, paramNames, scriptNames, returnTypes, result, importAlias, fileCache, isVariadic, variadicElem, origPkgName, srcPkgPath, config)
 
 	// 在文件开头写入导入（在代码生成完成后，但需要插入到文件开头）
 	content := b.String()
@@ -54,7 +65,7 @@ func writeFunctionStruct(b *strings.Builder, funcName string, fileCache *FileCac
 }
 
 // writeFunctionImplementation 写入函数实现
-func writeFunctionImplementation(b *strings.Builder, namePrefix, funcName string, paramTypes []reflect.Type, paramNames []string, returnTypes []reflect.Type, importAlias string, fileCache *FileCache, isVariadic bool, variadicElem reflect.Type, origPkgName string) {
+func writeFunctionImplementation(b *strings.Builder, namePrefix, funcName, callee string, paramTypes []reflect.Type, paramNames, scriptNames []string, returnTypes []reflect.Type, result *resultClass, importAlias string, fileCache *FileCache, isVariadic bool, variadicElem reflect.Type, origPkgName, srcPkgPath string, config *Config) {
 	fmt.Fprintf(b, "func (h *%sFunction) Call(ctx data.Context) (data.GetValue, data.Control) {\n", funcName)
 
 	// 标记使用的导入
@@ -83,87 +94,15 @@ func writeFunctionImplementation(b *strings.Builder, namePrefix, funcName string
 		if isVariadic {
 			endIdx = endIdx - 1
 		}
-		nextIndex = writeParameterConversion(b, paramTypes, paramNames, endIdx, fileCache, origPkgName, importAlias)
+		nextIndex = writeParameterConversion(b, paramTypes, paramNames, endIdx, fileCache, origPkgName, importAlias, srcPkgPath, config)
 		b.WriteString("\n")
 	}
 
 	// 处理可变参数（使用实际起始索引）
 	writeVariadicParameterHandling(b, isVariadic, variadicElem, paramNames, fileCache, origPkgName, importAlias, nextIndex)
 
-	// 函数调用（context.Context 改为 ctx.GoContext()）
-	if len(returnTypes) == 0 {
-		fmt.Fprintf(b, "\t%s.%s(", importAlias, funcName)
-		for i, pName := range paramNames {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			if isVariadic && i == len(paramNames)-1 {
-				fmt.Fprintf(b, "%s...", pName)
-			} else {
-				if i < len(paramTypes) && isContextType(paramTypes[i]) {
-					fmt.Fprintf(b, "ctx.GoContext()")
-				} else {
-					fmt.Fprintf(b, "%s", pName)
-				}
-			}
-		}
-		fmt.Fprintf(b, ")\n\treturn nil, nil\n")
-	} else if len(returnTypes) == 1 {
-		fmt.Fprintf(b, "\tret0 := %s.%s(", importAlias, funcName)
-		for i, pName := range paramNames {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			if isVariadic && i == len(paramNames)-1 {
-				fmt.Fprintf(b, "%s...", pName)
-			} else {
-				if i < len(paramTypes) && isContextType(paramTypes[i]) {
-					fmt.Fprintf(b, "ctx.GoContext()")
-				} else {
-					fmt.Fprintf(b, "%s", pName)
-				}
-			}
-		}
-		fmt.Fprintf(b, ")\n")
-		if returnTypes[0].Kind() == reflect.Ptr && returnTypes[0].Elem().Kind() == reflect.Struct {
-			fmt.Fprintf(b, "\treturn data.NewClassValue(New%sClassFrom(ret0), ctx), nil\n", returnTypes[0].Elem().Name())
-		} else {
-			fmt.Fprintf(b, "\treturn data.NewAnyValue(ret0), nil\n")
-		}
-	} else {
-		// 通用：支持任意个返回值（>=2）
-		b.WriteString("\t")
-		for i := range returnTypes {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			fmt.Fprintf(b, "ret%d", i)
-		}
-		fmt.Fprintf(b, " := %s.%s(", importAlias, funcName)
-		for i, pName := range paramNames {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			if isVariadic && i == len(paramNames)-1 {
-				fmt.Fprintf(b, "%s...", pName)
-			} else {
-				if i < len(paramTypes) && isContextType(paramTypes[i]) {
-					fmt.Fprintf(b, "ctx.GoContext()")
-				} else {
-					fmt.Fprintf(b, "%s", pName)
-				}
-			}
-		}
-		b.WriteString(")\n")
-		b.WriteString("\treturn data.NewArrayValue([]data.Value{")
-		for i := range returnTypes {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			fmt.Fprintf(b, "data.NewAnyValue(ret%d)", i)
-		}
-		b.WriteString("}), nil\n")
-	}
+	// 函数调用（context.Context 改为 ctx.GoContext()；末尾 error 转为脚本异常）
+	writeCallAndReturn(b, callee, paramTypes, paramNames, isVariadic, returnTypes, result, srcPkgPath, config, fileCache)
 
 	b.WriteString("}\n\n")
 
@@ -182,12 +121,12 @@ func writeFunctionImplementation(b *strings.Builder, namePrefix, funcName string
 	}
 	fmt.Fprintf(b, "func (h *%sFunction) GetParams() []data.GetValue { return []data.GetValue{\n", funcName)
 	idx := 0
-	for i, paramName := range paramNames {
+	for i := range paramNames {
 		if isContextType(paramTypes[i]) {
 			continue
 		}
-		datExpr := getDataTypeExpr(paramTypes[i])
-		fmt.Fprintf(b, "\t\tnode.NewParameter(nil, \"%s\", %d, nil, %s),\n", paramName, idx, datExpr)
+		datExpr := getDataTypeExpr(paramTypes[i], config, fileCache)
+		fmt.Fprintf(b, "\t\tnode.NewParameter(nil, \"%s\", %d, nil, %s),\n", scriptNames[i], idx, datExpr)
 		idx++
 	}
 	fmt.Fprintf(b, "\t}\n}\n")
@@ -195,29 +134,18 @@ func writeFunctionImplementation(b *strings.Builder, namePrefix, funcName string
 	// 变量清单（跳过 context.Context）
 	fmt.Fprintf(b, "func (h *%sFunction) GetVariables() []data.Variable { return []data.Variable{\n", funcName)
 	idx = 0
-	for i, paramName := range paramNames {
+	for i := range paramNames {
 		if isContextType(paramTypes[i]) {
 			continue
 		}
-		datExpr := getDataTypeExpr(paramTypes[i])
-		fmt.Fprintf(b, "\t\tnode.NewVariable(nil, \"%s\", %d, %s),\n", paramName, idx, datExpr)
+		datExpr := getDataTypeExpr(paramTypes[i], config, fileCache)
+		fmt.Fprintf(b, "\t\tnode.NewVariable(nil, \"%s\", %d, %s),\n", scriptNames[i], idx, datExpr)
 		idx++
 	}
 	fmt.Fprintf(b, "\t}\n}\n")
 
 	// 返回类型
-	if len(returnTypes) == 0 {
-		fmt.Fprintf(b, "func (h *%sFunction) GetReturnType() data.Types { return data.NewBaseType(\"void\") }\n", funcName)
-	} else if len(returnTypes) == 1 {
-		retTypeExpr := getDataTypeExpr(returnTypes[0])
-		fmt.Fprintf(b, "func (h *%sFunction) GetReturnType() data.Types { return %s }\n", funcName, retTypeExpr)
-	} else {
-		// 多返回值用数组类型
-		left := namePrefix
-		right := funcName + "Result"
-		retTypeExpr := fmt.Sprintf("data.NewBaseType(\"%s\\\\%s\")", left, right)
-		fmt.Fprintf(b, "func (h *%sFunction) GetReturnType() data.Types { return %s }\n", funcName, retTypeExpr)
-	}
+	fmt.Fprintf(b, "func (h *%sFunction) GetReturnType() data.Types { return %s }\n", funcName, getReturnTypeExpr(returnTypes, result, config, fileCache))
 }
 
 // analyzeFunctionParams 分析函数参数
@@ -258,8 +186,74 @@ func analyzeFunctionReturns(t reflect.Type) []reflect.Type {
 	return returnTypes
 }
 
+// getReturnTypeExpr 返回 GetReturnType 使用的类型表达式（末尾的 error 不计入）
+// - 无返回值：void
+// - 单返回值：对应的 data.* 类型
+// - 多返回值：生成的结果类 result
+func getReturnTypeExpr(returnTypes []reflect.Type, result *resultClass, config *Config, fileCache *FileCache) string {
+	// 末尾的 error 转为脚本异常，不计入返回类型
+	returnTypes, _ = splitErrorReturn(returnTypes)
+	switch len(returnTypes) {
+	case 0:
+		return "data.NewBaseType(\"void\")"
+	case 1:
+		return getDataTypeExpr(returnTypes[0], config, fileCache)
+	}
+	fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+	return fmt.Sprintf("utils.NewClassType(%q)", result.ScriptName)
+}
+
 // getDataTypeExpr 将 Go 类型映射为 data.* 类型表达式字符串
-func getDataTypeExpr(t reflect.Type) string {
-	// 简化处理，统一使用 nil
-	return "nil"
+// 无法在脚本中表达的类型（any、error、黑名单类型等）返回 "nil"，即不做类型检查
+//
+// 映射规则：
+// - 整数族 -> utils.IntType{}，浮点 -> utils.FloatType{}（非严格模式下也接受数值字符串），string -> data.String{}，bool -> data.Bool{}
+// - 切片、数组 -> data.Arrays{}；map -> utils.MapType{}（对象与数组都可转换为 map）
+// - 函数 -> data.NewNullableType(data.Callable{})（Go 中 nil 函数合法，脚本可传 null）
+// - channel -> data.NewNullableType(utils.NewClassType(utils.ChannelClassName))（nil channel 封装为 null）
+// - 生成类的结构体 -> utils.NewClassType("命名空间\\类名")，接口 -> utils.NewInterfaceType(...)
+// - 指针 -> data.NewNullableType(元素类型)
+func getDataTypeExpr(t reflect.Type, config *Config, fileCache *FileCache) string {
+	if t.Kind() == reflect.Ptr {
+		elemExpr := getDataTypeExpr(t.Elem(), config, fileCache)
+		if elemExpr == "nil" {
+			return "nil"
+		}
+		return "data.NewNullableType(" + elemExpr + ")"
+	}
+
+	if isGeneratedClassType(t, config) {
+		// data.Class 不接受 ProxyValue，使用 utils 中的类类型
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		if t.Kind() == reflect.Interface {
+			return fmt.Sprintf("utils.NewInterfaceType(%q)", scriptClassName(t, config))
+		}
+		return fmt.Sprintf("utils.NewClassType(%q)", scriptClassName(t, config))
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		return "utils.IntType{}"
+	case reflect.Float32, reflect.Float64:
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		return "utils.FloatType{}"
+	case reflect.String:
+		return "data.String{}"
+	case reflect.Bool:
+		return "data.Bool{}"
+	case reflect.Slice, reflect.Array:
+		return "data.Arrays{}"
+	case reflect.Map:
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		return "utils.MapType{}"
+	case reflect.Func:
+		return "data.NewNullableType(data.Callable{})"
+	case reflect.Chan:
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		return "data.NewNullableType(utils.NewClassType(utils.ChannelClassName))"
+	default:
+		return "nil"
+	}
 }
@@ -0,0 +1,317 @@
+package scr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Generics 泛型实例化模块
+//
+// 泛型函数与类型无法直接取值，需在配置 instantiations 中按包列出实例化，例如 Cache[string,*User]。
+// 源码分析阶段用 go/types 校验并解析类型实参，驱动程序据此引用实例化后的符号。
+// 生成时类名、函数名与文件名使用 基础名+类型实参 拼接的标识符（如 CacheStringPtrUser），不含方括号；
+// Go 代码中仍按 包别名.Cache[string, *别名.User] 引用源类型。
+
+// GenericFunc 实例化后的泛型函数
+//
+// 泛型函数值的 runtime 名称形如 pkg.Map[...]，得不到类型实参，需由调用方一并提供：
+//
+//	scr.GenericFunc{Name: "Map", PkgPath: "example.com/demo", Value: demo.Map[int, string],
+//		TypeArgs: []reflect.Type{reflect.TypeFor[int](), reflect.TypeFor[string]()}}
+type GenericFunc struct {
+	// Name 泛型函数名，例如 Map
+	Name string
+	// PkgPath 源包路径
+	PkgPath string
+	// Value 实例化后的函数值
+	Value any
+	// TypeArgs 类型实参，顺序与类型参数一致
+	TypeArgs []reflect.Type
+}
+
+// identName 生成使用的函数名，例如 Map[int, string] -> MapIntString
+func (g GenericFunc) identName() string {
+	b := &strings.Builder{}
+	b.WriteString(g.Name)
+	for _, arg := range g.TypeArgs {
+		b.WriteString(typeArgIdent(arg.String()))
+	}
+	return b.String()
+}
+
+// symbol 诊断与去重使用的符号名，例如 example.com/demo.Map[int,string]
+func (g GenericFunc) symbol() string {
+	args := make([]string, len(g.TypeArgs))
+	for i, arg := range g.TypeArgs {
+		args[i] = arg.String()
+	}
+	return g.PkgPath + "." + g.Name + "[" + strings.Join(args, ",") + "]"
+}
+
+// callee 生成代码中调用实例化函数的表达式，例如 demosrc.Map[int, string]
+func (g GenericFunc) callee(importAlias string, fileCache *FileCache) string {
+	args := make([]string, len(g.TypeArgs))
+	for i, arg := range g.TypeArgs {
+		useTypePackages(arg, fileCache)
+		args[i] = getTypeString(arg, fileCache)
+	}
+	return importAlias + "." + g.Name + "[" + strings.Join(args, ", ") + "]"
+}
+
+// useTypePackages 确保类型引用的包已导入并标记为使用
+func useTypePackages(t reflect.Type, fileCache *FileCache) {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		if t.Name() == "" {
+			useTypePackages(t.Elem(), fileCache)
+			return
+		}
+	case reflect.Map:
+		if t.Name() == "" {
+			useTypePackages(t.Key(), fileCache)
+			useTypePackages(t.Elem(), fileCache)
+			return
+		}
+	}
+	if t.PkgPath() != "" {
+		usePackage(t.PkgPath(), fileCache)
+	}
+}
+
+// usePackage 导入包（已有别名时沿用）并标记为使用
+func usePackage(pkgPath string, fileCache *FileCache) {
+	if _, ok := fileCache.GetImports()[pkgPath]; !ok {
+		fileCache.AddImport(pkgPath, pkgBaseName(pkgPath))
+	}
+	fileCache.MarkImportUsed(pkgPath)
+}
+
+// qualifiedNameRe 匹配 reflect 类型字符串中带完整包路径的类型名，例如 github.com/x/demo.User
+// 路径最后一段可能含点（gopkg.in/yaml.v3），由回溯保证取最后一个点之后的标识符
+var qualifiedNameRe = regexp.MustCompile(`((?:[\w.~-]+/)*[\w.~-]+)\.([A-Za-z_]\w*)`)
+
+// typeArgTokenRe 拼接标识符时保留的片段：切片标记、指针标记与标识符
+var typeArgTokenRe = regexp.MustCompile(`\[\]|\*|\w+`)
+
+// splitGenericName 拆分实例化类型名：Cache[string,*x.User] -> Cache, [string *x.User]
+// 非泛型类型返回原名与 nil
+func splitGenericName(name string) (string, []string) {
+	open := strings.Index(name, "[")
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return name, nil
+	}
+	var args []string
+	depth, start := 0, open+1
+	for i := start; i < len(name)-1; i++ {
+		switch name[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(name[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	args = append(args, strings.TrimSpace(name[start:len(name)-1]))
+	return name[:open], args
+}
+
+// typeArgIdent 类型实参对应的标识符片段：去掉包路径，切片记为 Slice，指针记为 Ptr，各段首字母大写
+// 例如 *github.com/x/demo.User -> PtrUser，[]string -> SliceString，map[string]int -> MapStringInt
+func typeArgIdent(arg string) string {
+	arg = qualifiedNameRe.ReplaceAllString(arg, "$2")
+	b := &strings.Builder{}
+	for _, tok := range typeArgTokenRe.FindAllString(arg, -1) {
+		switch tok {
+		case "[]":
+			b.WriteString("Slice")
+			continue
+		case "*":
+			b.WriteString("Ptr")
+			continue
+		}
+		b.WriteString(strings.ToUpper(tok[:1]) + tok[1:])
+	}
+	return b.String()
+}
+
+// typeIdentName 生成代码中使用的类型标识名：泛型实例为 基础名+类型实参，其余为类型名
+func typeIdentName(t reflect.Type) string {
+	base, args := splitGenericName(t.Name())
+	for _, arg := range args {
+		base += typeArgIdent(arg)
+	}
+	return base
+}
+
+// typeDeclName 类型在源码中的声明名（泛型实例去掉类型实参），用于查找源码信息
+func typeDeclName(t reflect.Type) string {
+	base, _ := splitGenericName(t.Name())
+	return base
+}
+
+// genericTypeName 泛型实例在 Go 代码中的写法：类型实参中的完整包路径替换为导入别名
+// 例如 Cache[string,*github.com/x/demo.User] -> Cache[string, *demosrc.User]
+func genericTypeName(t reflect.Type, fileCache *FileCache) string {
+	base, args := splitGenericName(t.Name())
+	if args == nil {
+		return t.Name()
+	}
+	for i, arg := range args {
+		args[i] = qualifiedNameRe.ReplaceAllStringFunc(arg, func(s string) string {
+			m := qualifiedNameRe.FindStringSubmatch(s)
+			if fileCache == nil {
+				return pkgBaseName(m[1]) + "." + m[2]
+			}
+			usePackage(m[1], fileCache)
+			alias := fileCache.GetImports()[m[1]]
+			if alias == "" {
+				alias = pkgBaseName(m[1])
+			}
+			return alias + "." + m[2]
+		})
+	}
+	return base + "[" + strings.Join(args, ", ") + "]"
+}
+
+// PackageInstance 配置中的一个泛型实例化，由 resolveInstances 校验解析
+type PackageInstance struct {
+	// Expr 配置原文，例如 Cache[string,*User]
+	Expr string
+	// Name 泛型符号名，例如 Cache
+	Name string
+	// Kind 符号种类：func / struct / interface
+	Kind string
+	// TypeArgs 类型实参
+	TypeArgs []types.Type
+}
+
+// resolveInstances 在包作用域中解析实例化表达式，校验符号存在、确为泛型且类型实参满足约束
+// 类型实参可以使用内置类型、包内类型以及包导入的其他包（如 time.Duration）
+// 不同实例化拼接出相同标识符时（如 Cache[a.User] 与 Cache[b.User]）返回错误，避免生成文件互相覆盖
+func resolveInstances(pkg *types.Package, exprs []string) ([]PackageInstance, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	scope := instanceScope(pkg)
+	fset := token.NewFileSet()
+
+	instances := make([]PackageInstance, 0, len(exprs))
+	idents := make(map[string]string, len(exprs))
+	for _, expr := range exprs {
+		base, argExprs, err := parseInstanceExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		obj := pkg.Scope().Lookup(base)
+		if obj == nil || !obj.Exported() {
+			return nil, fmt.Errorf("实例化 %s: 包 %s 中没有导出符号 %s", expr, pkg.Path(), base)
+		}
+		kind := genericSymbolKind(obj)
+		if kind == "" {
+			return nil, fmt.Errorf("实例化 %s: %s 不是泛型函数或泛型结构体/接口", expr, base)
+		}
+
+		args := make([]types.Type, len(argExprs))
+		for i, argExpr := range argExprs {
+			tv, err := types.Eval(fset, scope, token.NoPos, types.ExprString(argExpr))
+			if err != nil {
+				return nil, fmt.Errorf("实例化 %s: 类型实参 %s 无效: %w", expr, types.ExprString(argExpr), err)
+			}
+			if !tv.IsType() {
+				return nil, fmt.Errorf("实例化 %s: %s 不是类型", expr, types.ExprString(argExpr))
+			}
+			args[i] = tv.Type
+		}
+		if _, err := types.Instantiate(nil, obj.Type(), args, true); err != nil {
+			return nil, fmt.Errorf("实例化 %s 失败: %w", expr, err)
+		}
+		ident := instanceIdentName(base, args)
+		if prev, ok := idents[ident]; ok {
+			return nil, fmt.Errorf("实例化 %s 与 %s 生成的标识符同为 %s", expr, prev, ident)
+		}
+		idents[ident] = expr
+		instances = append(instances, PackageInstance{Expr: expr, Name: base, Kind: kind, TypeArgs: args})
+	}
+	return instances, nil
+}
+
+// instanceIdentName 实例化生成使用的标识符，与 typeIdentName、GenericFunc.identName 的拼接规则一致
+func instanceIdentName(base string, args []types.Type) string {
+	qualifier := func(p *types.Package) string { return p.Path() }
+	for _, arg := range args {
+		base += typeArgIdent(types.TypeString(arg, qualifier))
+	}
+	return base
+}
+
+// parseInstanceExpr 解析 Name[T1, T2] 形式的实例化表达式
+func parseInstanceExpr(expr string) (string, []ast.Expr, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", nil, fmt.Errorf("实例化 %s 语法错误: %w", expr, err)
+	}
+	var x ast.Expr
+	var indices []ast.Expr
+	switch e := node.(type) {
+	case *ast.IndexExpr:
+		x, indices = e.X, []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		x, indices = e.X, e.Indices
+	default:
+		return "", nil, fmt.Errorf("实例化 %s 不是 名称[类型实参] 形式", expr)
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok {
+		return "", nil, fmt.Errorf("实例化 %s 的符号名必须是包内标识符", expr)
+	}
+	return ident.Name, indices, nil
+}
+
+// genericSymbolKind 判断泛型符号的种类，非泛型或不支持的返回空字符串
+func genericSymbolKind(obj types.Object) string {
+	switch o := obj.(type) {
+	case *types.Func:
+		if sig, ok := o.Type().(*types.Signature); ok && sig.TypeParams().Len() > 0 {
+			return SymbolFunc
+		}
+	case *types.TypeName:
+		named, ok := o.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() == 0 {
+			return ""
+		}
+		switch u := named.Underlying().(type) {
+		case *types.Struct:
+			return SymbolStruct
+		case *types.Interface:
+			if u.IsMethodSet() {
+				return SymbolInterface
+			}
+		}
+	}
+	return ""
+}
+
+// instanceScope 构造求值类型实参用的包：包作用域中的符号加上包导入的其他包名
+// 包作用域本身不含文件级导入，直接求值无法解析 time.Duration 这类写法
+func instanceScope(pkg *types.Package) *types.Package {
+	eval := types.NewPackage(pkg.Path(), pkg.Name())
+	for _, name := range pkg.Scope().Names() {
+		eval.Scope().Insert(pkg.Scope().Lookup(name))
+	}
+	for _, imp := range pkg.Imports() {
+		if eval.Scope().Lookup(imp.Name()) == nil {
+			eval.Scope().Insert(types.NewPkgName(token.NoPos, eval, imp.Name(), imp))
+		}
+	}
+	return eval
+}
@@ -0,0 +1,70 @@
+package scr
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateInstantiationsBuild 按 instantiations 生成 demo 的泛型类与泛型函数，并编译生成的代码
+// golden 配置不含实例化，这里保证泛型实例的生成结果能通过编译
+func TestGenerateInstantiationsBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要运行驱动程序并编译生成的代码")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("找不到 go 命令")
+	}
+
+	// 输出目录须位于模块内部，生成的子包之间才能互相导入；_ 前缀使其不属于 ./... 的范围
+	root, err := os.MkdirTemp(".", "_out-generics-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+
+	const demoPath = "github.com/php-any/generator/demo"
+	config := &Config{OutputRoot: root, NamePrefix: "demo", Instantiations: map[string][]string{
+		demoPath: {"Cache[string,*User]", "Cache[int,time.Duration]", "Result[*User]", "NewCache[string,*User]", "Map[int,string]"},
+	}}
+	report, err := GenerateFromPackage(demoPath, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range report.Diagnostics {
+		if strings.Contains(d.Symbol, "Cache") || strings.Contains(d.Symbol, "Map") || strings.Contains(d.Symbol, "Result") {
+			t.Errorf("泛型实例生成失败: %s: %s", d.Symbol, d.Reason)
+		}
+	}
+
+	generated := make(map[string]bool, len(report.Files))
+	for _, p := range report.Files {
+		generated[filepath.ToSlash(p)] = true
+	}
+	for _, name := range []string{
+		"cachestringptruser_class.go", "cachestringptruser_get_result.go", "cacheintduration_class.go",
+		"resultptruser_class.go", "newcachestringptruser_func.go", "mapintstring_func.go",
+	} {
+		if p := filepath.ToSlash(filepath.Join(root, "demo", name)); !generated[p] {
+			t.Errorf("没有生成泛型实例文件 %s", p)
+		}
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"build"}
+	for _, e := range entries {
+		if e.IsDir() {
+			args = append(args, "./"+filepath.ToSlash(filepath.Join(root, e.Name())))
+		}
+	}
+	cmd := exec.Command(goBin, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("编译生成的代码失败: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,75 @@
+package scr
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// update 重新生成 testdata/golden 下的期望输出：go test ./scr -run TestGoldenDemo -update
+var update = flag.Bool("update", false, "更新 golden 文件")
+
+// TestGoldenDemo 为 demo 包运行完整的生成流程，对比 demo 输出子包与 golden 文件
+// 依赖标准库的输出子包（如 time）随 Go 版本变化，不参与对比
+func TestGoldenDemo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要运行驱动程序")
+	}
+
+	outputRoot := filepath.Join("testdata", "out")
+	out := NewMemoryFS()
+	config := &Config{OutputRoot: outputRoot, NamePrefix: "demo", Output: out}
+	if _, err := GenerateFromPackage("github.com/php-any/generator/demo", config); err != nil {
+		t.Fatal(err)
+	}
+
+	demoDir := filepath.Join(outputRoot, "demo")
+	goldenDir := filepath.Join("testdata", "golden", "demo")
+	generated := make(map[string][]byte)
+	for p, data := range out.Files() {
+		if filepath.Dir(p) == demoDir {
+			generated[filepath.Base(p)+".golden"] = data
+		}
+	}
+	if len(generated) == 0 {
+		t.Fatalf("没有生成 %s 下的文件", demoDir)
+	}
+
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		for name, data := range generated {
+			if err := (DiskFS{}).WriteFile(filepath.Join(goldenDir, name), data); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		t.Fatalf("读取 golden 目录失败（使用 -update 生成）: %v", err)
+	}
+	var goldenNames []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".golden") {
+			goldenNames = append(goldenNames, e.Name())
+		}
+	}
+	if names := sortedKeys(generated); !reflect.DeepEqual(names, goldenNames) {
+		t.Errorf("生成的文件 %q 与 golden 文件 %q 不一致", names, goldenNames)
+	}
+
+	for _, name := range goldenNames {
+		want, err := os.ReadFile(filepath.Join(goldenDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := generated[name]; ok && string(got) != string(want) {
+			t.Errorf("%s 与 golden 文件不一致（使用 -update 更新）:\n%s", name, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package scr
 
 import (
 	"fmt"
+	"path"
 	"reflect"
 	"strings"
 )
@@ -20,6 +21,7 @@ func collectClassImports(srcPkgPath, pkgName string, methods map[string]reflect.
 	// 新增：errors 与 utils 供 SetProperty/校验按需使用
 	fileCache.AddImport("errors", "")
 	fileCache.AddImport("time", "")
+	fileCache.AddImport("reflect", "")
 	fileCache.AddImport("github.com/php-any/generator/utils", "utils")
 
 	// 收集结构体字段需要的导入（只收集直接字段类型，避免过度递归）
@@ -45,27 +47,55 @@ func collectMethodImportsToCache(srcPkgPath, pkgName string, paramTypes []reflec
 	fileCache.AddImport("time", "")
 
 	// 收集标准库和第三方包的导入
-	allTypes := append(paramTypes, returnTypes...)
+	collectSignatureImports(append(paramTypes, returnTypes...), srcPkgPath, fileCache)
+}
+
+// collectSignatureImports 收集参数与返回值类型引用的包，含切片、map、指针等的元素类型
+// 例如 []time.Duration 需要导入 time
+func collectSignatureImports(allTypes []reflect.Type, srcPkgPath string, fileCache *FileCache) {
 	standardLibs := make(map[string]bool)
 	thirdPartyPkgs := make(map[string]bool)
 
-	for _, t := range allTypes {
-		if t.PkgPath() != "" && t.PkgPath() != srcPkgPath {
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		if t.PkgPath() != "" {
+			// internal 包无法从生成代码导入
+			if t.PkgPath() == srcPkgPath || isInternalPackage(t.PkgPath()) {
+				return
+			}
 			if isStandardLibrary(t.PkgPath()) {
 				standardLibs[t.PkgPath()] = true
 			} else {
 				thirdPartyPkgs[t.PkgPath()] = true
 			}
+			return
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+			walk(t.Elem())
+		case reflect.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case reflect.Func:
+			for i := 0; i < t.NumIn(); i++ {
+				walk(t.In(i))
+			}
+			for i := 0; i < t.NumOut(); i++ {
+				walk(t.Out(i))
+			}
 		}
 	}
+	for _, t := range allTypes {
+		walk(t)
+	}
 
 	// 添加标准库导入
-	for pkgPath := range standardLibs {
+	for _, pkgPath := range sortedKeys(standardLibs) {
 		fileCache.AddImport(pkgPath, "")
 	}
 
 	// 添加第三方包导入
-	for pkgPath := range thirdPartyPkgs {
+	for _, pkgPath := range sortedKeys(thirdPartyPkgs) {
 		fileCache.AddImport(pkgPath, pkgBaseName(pkgPath))
 	}
 }
@@ -106,6 +136,9 @@ func collectFunctionImportsToCache(srcPkgPath, pkgName string, paramTypes []refl
 	fileCache.AddImport("github.com/php-any/generator/utils", "utils")
 	// 新增：errors 供参数校验按需使用
 	fileCache.AddImport("errors", "")
+
+	// 收集参数与返回值类型引用的包
+	collectSignatureImports(append(paramTypes, returnTypes...), srcPkgPath, fileCache)
 }
 
 // writeImportsFromCache 从缓存写入导入
@@ -128,8 +161,9 @@ func writeImportsFromCache(b *strings.Builder, fileCache *FileCache) {
 	}
 
 	b.WriteString("import (\n")
-	for pkgPath, alias := range usedImports {
-		if alias == pkgBaseName(pkgPath) {
+	for _, pkgPath := range sortedKeys(usedImports) {
+		alias := usedImports[pkgPath]
+		if alias == "" || alias == path.Base(pkgPath) {
 			fmt.Fprintf(b, "\t\"%s\"\n", pkgPath)
 		} else {
 			fmt.Fprintf(b, "\t%s \"%s\"\n", alias, pkgPath)
@@ -145,8 +179,9 @@ func writeImports(b *strings.Builder, imports map[string]string) {
 	}
 
 	b.WriteString("import (\n")
-	for pkgPath, alias := range imports {
-		if alias == pkgBaseName(pkgPath) {
+	for _, pkgPath := range sortedKeys(imports) {
+		alias := imports[pkgPath]
+		if alias == "" || alias == path.Base(pkgPath) {
 			fmt.Fprintf(b, "\t\"%s\"\n", pkgPath)
 		} else {
 			fmt.Fprintf(b, "\t%s \"%s\"\n", alias, pkgPath)
@@ -178,6 +213,13 @@ func collectDirectTypeImports(t reflect.Type, srcPkgPath string, fileCache *File
 		return
 	}
 
+	// 具名的切片、map 等类型（如 asn1.ObjectIdentifier）同样按类型名引用，需要导入所属包
+	if t.Name() != "" && t.PkgPath() != "" && t.PkgPath() != srcPkgPath {
+		if config != nil && !isBlacklistedPackage(t.PkgPath(), config) {
+			fileCache.AddImport(t.PkgPath(), pkgBaseName(t.PkgPath()))
+		}
+	}
+
 	switch t.Kind() {
 	case reflect.Ptr:
 		collectDirectTypeImports(t.Elem(), srcPkgPath, fileCache, config)
@@ -219,6 +261,13 @@ func collectTypeImportsWithVisited(t reflect.Type, srcPkgPath string, fileCache
 	}
 	visited[t] = true
 
+	// 具名的切片、map 等类型（如 asn1.ObjectIdentifier）同样按类型名引用，需要导入所属包
+	if t.Name() != "" && t.PkgPath() != "" && t.PkgPath() != srcPkgPath {
+		if config != nil && !isBlacklistedPackage(t.PkgPath(), config) {
+			fileCache.AddImport(t.PkgPath(), pkgBaseName(t.PkgPath()))
+		}
+	}
+
 	switch t.Kind() {
 	case reflect.Ptr:
 		collectTypeImportsWithVisited(t.Elem(), srcPkgPath, fileCache, config, visited)
@@ -263,6 +312,12 @@ func collectTypeImportsWithVisited(t reflect.Type, srcPkgPath string, fileCache
 	}
 }
 
+// isInternalPackage 判断是否为 internal 包（路径中含 internal 段）
+func isInternalPackage(pkgPath string) bool {
+	return pkgPath == "internal" || strings.HasPrefix(pkgPath, "internal/") ||
+		strings.Contains(pkgPath, "/internal/") || strings.HasSuffix(pkgPath, "/internal")
+}
+
 // isBlacklistedPackage 检查包是否在黑名单中
 func isBlacklistedPackage(pkgPath string, config *Config) bool {
 	if config == nil || config.Blacklist.Packages == nil {
@@ -277,11 +332,3 @@ func isBlacklistedPackage(pkgPath string, config *Config) bool {
 
 	return false
 }
-
-// isStructType 检查类型是否为结构体类型
-func isStructType(t reflect.Type) bool {
-	for t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
-	return t.Kind() == reflect.Struct
-}
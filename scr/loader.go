@@ -0,0 +1,148 @@
+package scr
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Loader 源码分析模块，基于 go/packages + go/types 发现包内的可导出符号
+
+// 符号种类
+const (
+	SymbolFunc      = "func"
+	SymbolStruct    = "struct"
+	SymbolInterface = "interface"
+)
+
+// PackageSymbol 包内可导出的顶级符号
+type PackageSymbol struct {
+	// 符号名，例如: NewClient
+	Name string
+	// 符号种类：func / struct / interface
+	Kind string
+}
+
+// PackageInfo 包的源码分析结果
+type PackageInfo struct {
+	// 完整导入路径，例如: github.com/redis/go-redis/v9
+	Path string
+	// 源码中声明的包名，例如: redis
+	Name string
+	// 按名称排序的可导出符号
+	Symbols []PackageSymbol
+	// 配置中的泛型实例化，按配置顺序
+	Instances []PackageInstance
+
+	// types 类型检查结果，用于解析泛型实例化
+	types *types.Package
+}
+
+// LoadPackage 通过导入路径加载包，收集全部导出的函数、结构体与接口
+func LoadPackage(importPath string) (*PackageInfo, error) {
+	if importPath == "" {
+		return nil, errors.New("导入路径为空")
+	}
+	infos, err := LoadPackages([]string{importPath})
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) != 1 {
+		return nil, fmt.Errorf("导入路径 %s 匹配到 %d 个包，期望 1 个", importPath, len(infos))
+	}
+	return infos[0], nil
+}
+
+// LoadPackages 在一次 packages.Load 中加载多个导入路径，共享依赖的解析与类型检查
+// 结果按导入路径排序，指向同一个包的路径（如 ./demo 与完整导入路径）只返回一次
+func LoadPackages(importPaths []string) ([]*PackageInfo, error) {
+	if len(importPaths) == 0 {
+		return nil, errors.New("缺少导入路径")
+	}
+	if slices.Contains(importPaths, "") {
+		return nil, errors.New("导入路径为空")
+	}
+
+	// 从源码完成类型检查，不依赖与工具链版本绑定的导出数据
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps,
+	}
+	pkgs, err := packages.Load(cfg, importPaths...)
+	if err != nil {
+		return nil, fmt.Errorf("加载包 %s 失败: %w", strings.Join(importPaths, " "), err)
+	}
+
+	infos := make([]*PackageInfo, 0, len(pkgs))
+	seen := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("加载包 %s 失败: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		if pkg.Types == nil {
+			return nil, fmt.Errorf("包 %s 缺少类型信息", pkg.PkgPath)
+		}
+		if seen[pkg.PkgPath] {
+			continue
+		}
+		seen[pkg.PkgPath] = true
+		infos = append(infos, &PackageInfo{
+			Path:    pkg.PkgPath,
+			Name:    pkg.Name,
+			Symbols: collectPackageSymbols(pkg.Types.Scope()),
+			types:   pkg.Types,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Path < infos[j].Path })
+	return infos, nil
+}
+
+// collectPackageSymbols 遍历包作用域，筛选可生成的导出符号
+func collectPackageSymbols(scope *types.Scope) []PackageSymbol {
+	var symbols []PackageSymbol
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		if kind := symbolKind(obj); kind != "" {
+			symbols = append(symbols, PackageSymbol{Name: name, Kind: kind})
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Name < symbols[j].Name })
+	return symbols
+}
+
+// symbolKind 判断对象对应的符号种类，不支持的返回空字符串
+func symbolKind(obj types.Object) string {
+	switch o := obj.(type) {
+	case *types.Func:
+		sig, ok := o.Type().(*types.Signature)
+		if !ok || sig.TypeParams().Len() > 0 {
+			// 泛型函数无法直接取值，需在配置 instantiations 中实例化
+			return ""
+		}
+		return SymbolFunc
+	case *types.TypeName:
+		named, ok := o.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			// 泛型类型需要先实例化，见配置 instantiations
+			return ""
+		}
+		switch u := named.Underlying().(type) {
+		case *types.Struct:
+			return SymbolStruct
+		case *types.Interface:
+			// 类型约束接口（含 ~int | string 等）不能作为值使用
+			if !u.IsMethodSet() {
+				return ""
+			}
+			return SymbolInterface
+		}
+	}
+	return ""
+}
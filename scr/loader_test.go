@@ -0,0 +1,29 @@
+package scr
+
+import (
+	"testing"
+)
+
+func TestLoadPackages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("加载包需要类型检查源码")
+	}
+	infos, err := LoadPackages([]string{"time", "../demo", "github.com/php-any/generator/demo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, info := range infos {
+		paths = append(paths, info.Path)
+	}
+	if len(paths) != 2 || paths[0] != "github.com/php-any/generator/demo" || paths[1] != "time" {
+		t.Fatalf("LoadPackages 返回 %q, 期望按路径排序且去重", paths)
+	}
+	if len(infos[0].Symbols) == 0 {
+		t.Error("demo 包应包含可生成的符号")
+	}
+
+	if _, err := LoadPackages([]string{"time", ""}); err == nil {
+		t.Error("空导入路径期望返回 error")
+	}
+}
@@ -0,0 +1,333 @@
+package scr
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Manifest 生成清单模块
+//
+// 清单记录生成器拥有的全部文件（相对 OutputRoot 的路径，首段为输出子包），按请求生成的输出子包分段：
+//
+//	[demo]
+//	demo/load.go
+//	time/time_class.go
+//
+// 每段包含该子包自身的文件，以及生成它时一并生成的依赖子包文件。再次生成时，
+// 本次请求的子包（Report.Packages）的段整体替换为本次生成的文件，其他段原样保留，
+// 分多次生成不同的包时互不影响；不再出现在任何段中的文件视为孤立文件并被删除，
+// 依赖子包不再被任何请求使用（如加入黑名单）时随之清理。
+// 删除前会确认文件仍带有生成标记，手写文件永远不会被删除。
+
+// ManifestName 清单文件名，位于 OutputRoot 下
+const ManifestName = "origami-gen.manifest"
+
+// manifestHeader 清单文件头
+const manifestHeader = "# 由 origami-gen 维护的生成文件清单，请勿手动修改"
+
+// manifestPlan 一次生成后的清单同步计划
+type manifestPlan struct {
+	// 清单文件路径
+	path string
+	// 新的清单内容
+	content []byte
+	// 应删除的孤立文件（均存在且带生成标记）
+	orphans []string
+}
+
+// SyncManifest 根据本次生成的文件写出清单，并删除上次生成、本次未再生成的孤立文件
+// 应在一次生成的全部符号完成后调用，返回被删除的文件
+func SyncManifest(config *Config, report *Report) ([]string, error) {
+	plan, err := planManifest(config, report)
+	if err != nil {
+		return nil, err
+	}
+
+	out := outputFS(config)
+	var removed []string
+	for _, p := range plan.orphans {
+		if err := out.Remove(p); err != nil {
+			return removed, fmt.Errorf("删除孤立文件 %s 失败: %w", p, err)
+		}
+		removed = append(removed, p)
+	}
+	if err := out.WriteFile(plan.path, plan.content); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+// planManifest 对比磁盘上的清单与本次生成的文件，计算新清单与孤立文件
+func planManifest(config *Config, report *Report) (*manifestPlan, error) {
+	root := config.OutputRoot
+	plan := &manifestPlan{path: filepath.Join(root, ManifestName)}
+
+	previous, err := readManifest(plan.path)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := ownedPackages(config, report.Packages)
+	sections := make(map[string][]string, len(previous)+len(owned))
+	for pkg, entries := range previous {
+		if !owned[pkg] {
+			sections[pkg] = entries
+		}
+	}
+	// 本次请求的子包各自记录自身文件与全部依赖子包的文件
+	for _, p := range report.Files {
+		rel, ok := manifestEntry(root, p)
+		if !ok {
+			continue
+		}
+		filePkg := manifestPackage(rel)
+		for pkg := range owned {
+			if filePkg == pkg || !owned[filePkg] {
+				sections[pkg] = append(sections[pkg], rel)
+			}
+		}
+	}
+
+	live := manifestFiles(sections)
+	for _, rel := range sortedKeys(manifestFiles(previous)) {
+		if live[rel] {
+			continue
+		}
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		data, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 没有生成标记的文件可能已被手工接管，保留文件但不再记录
+		if !isGeneratedFile(data) {
+			continue
+		}
+		plan.orphans = append(plan.orphans, p)
+	}
+
+	b := &bytes.Buffer{}
+	b.WriteString(manifestHeader)
+	b.WriteString("\n")
+	for _, pkg := range sortedKeys(sections) {
+		entries := sortedKeys(manifestFiles(map[string][]string{pkg: sections[pkg]}))
+		if len(entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "[%s]\n", pkg)
+		for _, rel := range entries {
+			b.WriteString(rel)
+			b.WriteString("\n")
+		}
+	}
+	plan.content = b.Bytes()
+	return plan, nil
+}
+
+// ownedPackages 返回源包对应的输出子包集合
+func ownedPackages(config *Config, pkgPaths []string) map[string]bool {
+	owned := make(map[string]bool, len(pkgPaths))
+	for _, pkgPath := range pkgPaths {
+		owned[outputPackageName(pkgPath, config)] = true
+	}
+	return owned
+}
+
+// manifestFiles 合并各段的条目
+func manifestFiles(sections map[string][]string) map[string]bool {
+	files := make(map[string]bool)
+	for _, entries := range sections {
+		for _, rel := range entries {
+			files[rel] = true
+		}
+	}
+	return files
+}
+
+// Clean 删除清单记录的生成文件与清单本身，随后删除因此变空的目录，返回被删除的文件与因不带生成标记而保留的文件
+// 只删除仍带有生成标记的文件；输出目录下没有清单时拒绝清理
+func Clean(config *Config) (removed, kept []string, err error) {
+	root := config.OutputRoot
+	manifestPath := filepath.Join(root, ManifestName)
+	if _, err := os.Stat(manifestPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("%s 下没有清单 %s，拒绝清理", root, ManifestName)
+		}
+		return nil, nil, err
+	}
+	sections, err := readManifest(manifestPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dirs := make(map[string]bool)
+	for _, rel := range sortedKeys(manifestFiles(sections)) {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		data, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return removed, kept, err
+		}
+		if !isGeneratedFile(data) {
+			kept = append(kept, p)
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			return removed, kept, fmt.Errorf("删除 %s 失败: %w", p, err)
+		}
+		removed = append(removed, p)
+		dirs[filepath.Dir(p)] = true
+	}
+	if err := os.Remove(manifestPath); err != nil {
+		return removed, kept, fmt.Errorf("删除清单 %s 失败: %w", manifestPath, err)
+	}
+	removed = append(removed, manifestPath)
+	dirs[root] = true
+
+	// 由深到浅删除空目录，直到 OutputRoot 为止
+	paths := sortedKeys(dirs)
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	for _, dir := range paths {
+		if err := removeEmptyDirs(dir, root); err != nil {
+			return removed, kept, err
+		}
+	}
+	return removed, kept, nil
+}
+
+// removeEmptyDirs 自 dir 向上逐级删除空目录，不越过 root
+func removeEmptyDirs(dir, root string) error {
+	for {
+		if _, ok := manifestEntry(root, dir); !ok && dir != root {
+			return nil
+		}
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return nil
+		}
+		if err := os.Remove(dir); err != nil {
+			return fmt.Errorf("删除空目录 %s 失败: %w", dir, err)
+		}
+		if dir == root {
+			return nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// readManifest 读取清单中按段记录的文件列表，清单不存在时返回空
+// 不分段的旧格式条目无法判断由哪个请求使用，归入其所在输出子包的段保守保留
+func readManifest(manifestPath string) (map[string][]string, error) {
+	data, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make(map[string][]string)
+	section, inSection := "", false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section, inSection = line[1:len(line)-1], true
+			continue
+		}
+		// 忽略指向 OutputRoot 之外的条目，防止误删
+		if !filepath.IsLocal(filepath.FromSlash(line)) {
+			continue
+		}
+		pkg := section
+		if !inSection {
+			pkg = manifestPackage(line)
+		}
+		sections[pkg] = append(sections[pkg], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取清单 %s 失败: %w", manifestPath, err)
+	}
+	for _, entries := range sections {
+		sort.Strings(entries)
+	}
+	return sections, nil
+}
+
+// registeredNameRe 匹配生成文件中 load.go 需要注册的类与函数构造函数
+var registeredNameRe = regexp.MustCompile(`(?m)^func New(\w+)(Class|Function)\(\) data\.(ClassStmt|FuncStmt) \{$`)
+
+// carriedRegistrations 返回清单中其他请求的段仍在使用的 pkgName 子包文件中声明的类与函数
+// 这些文件不属于本次生成，但仍保留在磁盘上，load.go 须继续注册它们
+func carriedRegistrations(config *Config, pkgName string, owned map[string]bool) (classes, functions []string, err error) {
+	sections, err := readManifest(filepath.Join(config.OutputRoot, ManifestName))
+	if err != nil {
+		return nil, nil, err
+	}
+	carried := make(map[string][]string, len(sections))
+	for pkg, entries := range sections {
+		if !owned[pkg] {
+			carried[pkg] = entries
+		}
+	}
+	for _, rel := range sortedKeys(manifestFiles(carried)) {
+		if manifestPackage(rel) != pkgName || rel == pkgName+"/load.go" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(config.OutputRoot, filepath.FromSlash(rel)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isGeneratedFile(data) {
+			continue
+		}
+		for _, m := range registeredNameRe.FindAllSubmatch(data, -1) {
+			if string(m[2]) == "Class" {
+				classes = append(classes, string(m[1]))
+			} else {
+				functions = append(functions, string(m[1]))
+			}
+		}
+	}
+	return classes, functions, nil
+}
+
+// manifestEntry 返回文件相对 OutputRoot 的清单条目，不在 OutputRoot 下时返回 false
+func manifestEntry(root, p string) (string, bool) {
+	rel, err := filepath.Rel(root, p)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// manifestPackage 返回清单条目所属的输出子包（路径首段），位于 OutputRoot 根下的条目返回空
+func manifestPackage(rel string) string {
+	pkg, _, ok := strings.Cut(rel, "/")
+	if !ok {
+		return ""
+	}
+	return pkg
+}
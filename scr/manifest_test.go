@@ -0,0 +1,318 @@
+package scr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testGenerated = GeneratedMarker + "\n\npackage a\n"
+	testHandWrite = "package a\n"
+)
+
+// writeTree 在 root 下写入文件，键为相对路径
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		if err := (DiskFS{}).WriteFile(filepath.Join(root, filepath.FromSlash(rel)), []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// manifestContent 按行拼出清单文件内容，"[pkg]" 为段头
+func manifestContent(entries ...string) string {
+	return manifestHeader + "\n" + strings.Join(append(entries, ""), "\n")
+}
+
+// rootPaths 将相对路径转换为 root 下的路径，空列表返回 nil
+func rootPaths(root string, rels []string) []string {
+	var paths []string
+	for _, rel := range rels {
+		paths = append(paths, filepath.Join(root, filepath.FromSlash(rel)))
+	}
+	return paths
+}
+
+// testReport 构造一次生成的报告，files 为相对 root 的路径
+func testReport(root string, files []string, packages ...string) *Report {
+	return &Report{Files: rootPaths(root, files), Packages: packages}
+}
+
+func TestSyncManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		disk     map[string]string
+		files    []string
+		packages []string
+		removed  []string
+		manifest []string
+		kept     []string
+	}{
+		{
+			name:     "首次生成写出清单",
+			disk:     map[string]string{"a/x.go": testGenerated},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go"},
+		},
+		{
+			name: "删除本包的孤立文件",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/old.go", "a/x.go"),
+				"a/x.go":     testGenerated,
+				"a/old.go":   testGenerated,
+			},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			removed:  []string{"a/old.go"},
+			manifest: []string{"[a]", "a/x.go"},
+		},
+		{
+			name: "保留没有生成标记的文件",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/hand.go", "a/x.go"),
+				"a/x.go":     testGenerated,
+				"a/hand.go":  testHandWrite,
+			},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go"},
+			kept:     []string{"a/hand.go"},
+		},
+		{
+			name: "丢弃磁盘上已不存在的条目",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/gone.go", "a/x.go"),
+				"a/x.go":     testGenerated,
+			},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go"},
+		},
+		{
+			name: "删除本次不再生成的依赖子包",
+			disk: map[string]string{
+				ManifestName:   manifestContent("[a]", "a/x.go", "time/load.go", "time/t.go"),
+				"a/x.go":       testGenerated,
+				"time/load.go": testGenerated,
+				"time/t.go":    testGenerated,
+			},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			removed:  []string{"time/load.go", "time/t.go"},
+			manifest: []string{"[a]", "a/x.go"},
+		},
+		{
+			name: "其他包及其依赖子包原样保留",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "a/x.go", "time/t.go", "[b]", "b/z.go", "time/t.go"),
+				"a/x.go":     testGenerated,
+				"b/z.go":     testGenerated,
+				"time/t.go":  testGenerated,
+			},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go", "[b]", "b/z.go", "time/t.go"},
+			kept:     []string{"b/z.go", "time/t.go"},
+		},
+		{
+			name: "旧格式清单按子包归段",
+			disk: map[string]string{
+				ManifestName: manifestContent("a/x.go", "b/z.go", "time/t.go"),
+				"a/x.go":     testGenerated,
+				"b/z.go":     testGenerated,
+				"time/t.go":  testGenerated,
+			},
+			// 旧格式无法判断依赖子包由哪个包使用，按所在子包保守保留
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go", "[b]", "b/z.go", "[time]", "time/t.go"},
+			kept:     []string{"b/z.go", "time/t.go"},
+		},
+		{
+			name: "忽略指向输出目录之外的条目",
+			disk: map[string]string{
+				ManifestName: manifestContent("[a]", "../a/x.go", "a/x.go"),
+				"a/x.go":     testGenerated,
+			},
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tt.disk)
+			config := &Config{OutputRoot: root}
+
+			removed, err := SyncManifest(config, testReport(root, tt.files, tt.packages...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := rootPaths(root, tt.removed); !reflect.DeepEqual(removed, want) {
+				t.Errorf("删除 %q, 期望 %q", removed, want)
+			}
+			for _, p := range removed {
+				if _, err := os.Stat(p); !os.IsNotExist(err) {
+					t.Errorf("%s 应已删除", p)
+				}
+			}
+			for _, p := range rootPaths(root, tt.kept) {
+				if _, err := os.Stat(p); err != nil {
+					t.Errorf("%s 应保留: %v", p, err)
+				}
+			}
+			got, err := os.ReadFile(filepath.Join(root, ManifestName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := manifestContent(tt.manifest...); string(got) != want {
+				t.Errorf("清单 =\n%s期望\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestSyncManifestMultiplePackages 分多次生成不同的包，每次只替换本次请求的包的段
+func TestSyncManifestMultiplePackages(t *testing.T) {
+	steps := []struct {
+		files    []string
+		packages []string
+		removed  []string
+		manifest []string
+	}{
+		{
+			files:    []string{"a/x.go", "a/y.go", "time/t.go"},
+			packages: []string{"example.com/a"},
+			manifest: []string{"[a]", "a/x.go", "a/y.go", "time/t.go"},
+		},
+		{
+			files:    []string{"b/z.go", "time/t.go"},
+			packages: []string{"example.com/b"},
+			manifest: []string{"[a]", "a/x.go", "a/y.go", "time/t.go", "[b]", "b/z.go", "time/t.go"},
+		},
+		{
+			// a 不再依赖 time，b 仍在使用，time 保留
+			files:    []string{"a/x.go"},
+			packages: []string{"example.com/a"},
+			removed:  []string{"a/y.go"},
+			manifest: []string{"[a]", "a/x.go", "[b]", "b/z.go", "time/t.go"},
+		},
+		{
+			files:    []string{"a/x.go", "b/w.go"},
+			packages: []string{"example.com/a", "example.com/b"},
+			removed:  []string{"b/z.go", "time/t.go"},
+			manifest: []string{"[a]", "a/x.go", "[b]", "b/w.go"},
+		},
+	}
+
+	root := t.TempDir()
+	config := &Config{OutputRoot: root}
+	for i, step := range steps {
+		for _, rel := range step.files {
+			writeTree(t, root, map[string]string{rel: testGenerated})
+		}
+		removed, err := SyncManifest(config, testReport(root, step.files, step.packages...))
+		if err != nil {
+			t.Fatalf("第 %d 次生成: %v", i+1, err)
+		}
+		if want := rootPaths(root, step.removed); !reflect.DeepEqual(removed, want) {
+			t.Errorf("第 %d 次生成删除 %q, 期望 %q", i+1, removed, want)
+		}
+		got, err := os.ReadFile(filepath.Join(root, ManifestName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := manifestContent(step.manifest...); string(got) != want {
+			t.Errorf("第 %d 次生成清单 =\n%s期望\n%s", i+1, got, want)
+		}
+	}
+}
+
+func TestCarriedRegistrations(t *testing.T) {
+	root := t.TempDir()
+	class := GeneratedMarker + "\n\npackage time\n\nfunc NewDurationClass() data.ClassStmt {\n\treturn nil\n}\n"
+	function := GeneratedMarker + "\n\npackage time\n\nfunc NewSinceFunction() data.FuncStmt {\n\treturn nil\n}\n"
+	writeTree(t, root, map[string]string{
+		ManifestName:             manifestContent("[a]", "a/x.go", "time/old_class.go", "[b]", "b/z.go", "time/duration_class.go", "time/since_func.go"),
+		"time/old_class.go":      strings.ReplaceAll(class, "Duration", "Old"),
+		"time/duration_class.go": class,
+		"time/since_func.go":     function,
+	})
+	config := &Config{OutputRoot: root}
+
+	// 重新生成 a：只保留 b 段仍在使用的文件中的注册
+	classes, functions, err := carriedRegistrations(config, "time", map[string]bool{"a": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(classes, []string{"Duration"}) || !reflect.DeepEqual(functions, []string{"Since"}) {
+		t.Errorf("carriedRegistrations = %q, %q", classes, functions)
+	}
+
+	classes, functions, err = carriedRegistrations(config, "time", map[string]bool{"a": true, "b": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 0 || len(functions) != 0 {
+		t.Errorf("重新生成全部包时不应保留注册: %q, %q", classes, functions)
+	}
+}
+
+func TestClean(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "out")
+	writeTree(t, root, map[string]string{
+		ManifestName:  manifestContent("[a]", "a/hand.go", "a/x.go", "time/t.go", "[b]", "b/z.go"),
+		"a/x.go":      testGenerated,
+		"a/hand.go":   testHandWrite,
+		"a/other.go":  testGenerated,
+		"time/t.go":   testGenerated,
+		"b/z.go":      testGenerated,
+		"b/sub/y.txt": "未记录",
+	})
+
+	removed, kept, err := Clean(&Config{OutputRoot: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := rootPaths(root, []string{"a/x.go", "b/z.go", "time/t.go", ManifestName}); !reflect.DeepEqual(removed, want) {
+		t.Errorf("删除 %q, 期望 %q", removed, want)
+	}
+	if want := rootPaths(root, []string{"a/hand.go"}); !reflect.DeepEqual(kept, want) {
+		t.Errorf("保留 %q, 期望 %q", kept, want)
+	}
+	// 未记录在清单中的文件及其目录保留，清空的目录删除
+	for _, rel := range []string{"a/hand.go", "a/other.go", "b/sub/y.txt"} {
+		if _, err := os.Stat(filepath.Join(root, rel)); err != nil {
+			t.Errorf("%s 应保留: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "time")); !os.IsNotExist(err) {
+		t.Errorf("空目录 time 应已删除: %v", err)
+	}
+
+	// 清单已删除，再次清理被拒绝
+	if _, _, err := Clean(&Config{OutputRoot: root}); err == nil {
+		t.Error("没有清单时期望返回 error")
+	}
+}
+
+func TestCleanRemovesEmptyRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "out")
+	writeTree(t, root, map[string]string{
+		ManifestName: manifestContent("[a]", "a/x.go"),
+		"a/x.go":     testGenerated,
+	})
+	if _, _, err := Clean(&Config{OutputRoot: root}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("清空后的输出目录应已删除: %v", err)
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // buildMethodFileBody 构建方法文件内容
-func buildMethodFileBody(srcPkgPath, pkgName, typeName string, m reflect.Method, sourceIsPtr bool, fileCache *FileCache, structType reflect.Type, config *Config) (string, bool) {
+func buildMethodFileBody(srcPkgPath, pkgName, typeName string, m reflect.Method, sourceIsPtr bool, result *resultClass, fileCache *FileCache, structType reflect.Type, config *Config) (string, bool) {
 	b := &strings.Builder{}
 	importAlias := pkgName + "src"
 
@@ -18,11 +18,16 @@ func buildMethodFileBody(srcPkgPath, pkgName, typeName string, m reflect.Method,
 	// 收集导入
 	collectMethodImportsToCache(srcPkgPath, pkgName, paramTypes, returnTypes, fileCache, config)
 
+	// 参数名：源码中声明的名称优先，启发式兜底；脚本中保持声明的名称，Go 局部变量冲突时追加后缀
+	sourceNames, _ := sourceParamIndex(srcPkgPath).methodSignature(typeDeclName(structType), m.Name)
+	scriptNames, paramNames := resolveParamNames(sourceNames.params, paramTypes, m.Name, isVariadic, fileCache)
+
 	// 生成方法结构体
 	writeMethodStruct(b, typeName, m.Name, importAlias, structType, fileCache, srcPkgPath)
 
-	// 生成方法实现
-	writeMethodImplementation(b, typeName, m.Name, paramTypes, paramNames, returnTypes, importAlias, fileCache, isVariadic, variadicElem)
+	// 生成方法实现（源包在 Go 代码中的包名，用于替换为导入别名）
+	origPkgName := pkgBaseName(srcPkgPath)
+	writeMethodImplementation(b, typeName, m.Name, paramTypes, paramNames, scriptNames, returnTypes, result, importAlias, fileCache, isVariadic, variadicElem, origPkgName, srcPkgPath, config)
 
 	// 在文件开头写入导入（在代码生成完成后，但需要插入到文件开头）
 	content := b.String()
@@ -43,15 +48,15 @@ func writeMethodStruct(b *strings.Builder, typeName, methodName, importAlias str
 
 	fmt.Fprintf(b, "type %s%sMethod struct {\n", typeName, methodName)
 	if structType.Kind() == reflect.Interface {
-		fmt.Fprintf(b, "\tsource %s.%s\n", importAlias, typeName)
+		fmt.Fprintf(b, "\tsource %s.%s\n", importAlias, genericTypeName(structType, fileCache))
 	} else {
-		fmt.Fprintf(b, "\tsource *%s.%s\n", importAlias, typeName)
+		fmt.Fprintf(b, "\tsource *%s.%s\n", importAlias, genericTypeName(structType, fileCache))
 	}
 	b.WriteString("}\n\n")
 }
 
 // writeMethodImplementation 写入方法实现
-func writeMethodImplementation(b *strings.Builder, typeName, methodName string, paramTypes []reflect.Type, paramNames []string, returnTypes []reflect.Type, importAlias string, fileCache *FileCache, isVariadic bool, variadicElem reflect.Type) {
+func writeMethodImplementation(b *strings.Builder, typeName, methodName string, paramTypes []reflect.Type, paramNames, scriptNames []string, returnTypes []reflect.Type, result *resultClass, importAlias string, fileCache *FileCache, isVariadic bool, variadicElem reflect.Type, origPkgName, srcPkgPath string, config *Config) {
 	fmt.Fprintf(b, "func (h *%s%sMethod) Call(ctx data.Context) (data.GetValue, data.Control) {\n", typeName, methodName)
 
 	// 标记使用的导入
@@ -80,97 +85,15 @@ func writeMethodImplementation(b *strings.Builder, typeName, methodName string,
 		if isVariadic {
 			endIdx = endIdx - 1
 		}
-		// 从 importAlias 反推出原包短名（去掉 src 后缀）
-		origPkgName := ""
-		if strings.HasSuffix(importAlias, "src") {
-			origPkgName = strings.TrimSuffix(importAlias, "src")
-		}
-		nextIndex = writeParameterConversion(b, paramTypes, paramNames, endIdx, fileCache, origPkgName, importAlias)
+		nextIndex = writeParameterConversion(b, paramTypes, paramNames, endIdx, fileCache, origPkgName, importAlias, srcPkgPath, config)
 		b.WriteString("\n")
 	}
 
 	// 处理可变参数（使用实际起始索引）
-	origPkgName := ""
-	if strings.HasSuffix(importAlias, "src") {
-		origPkgName = strings.TrimSuffix(importAlias, "src")
-	}
 	writeVariadicParameterHandling(b, isVariadic, variadicElem, paramNames, fileCache, origPkgName, importAlias, nextIndex)
 
-	// 方法调用（context.Context 改为 ctx.GoContext()）
-	sourceCall := "h.source"
-	if len(returnTypes) == 0 {
-		fmt.Fprintf(b, "\t%s.%s(", sourceCall, methodName)
-		for i, pName := range paramNames {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			if isVariadic && i == len(paramNames)-1 {
-				fmt.Fprintf(b, "%s...", pName)
-			} else {
-				if i < len(paramTypes) && isContextType(paramTypes[i]) {
-					fmt.Fprintf(b, "ctx.GoContext()")
-				} else {
-					fmt.Fprintf(b, "%s", pName)
-				}
-			}
-		}
-		fmt.Fprintf(b, ")\n\treturn nil, nil\n")
-	} else if len(returnTypes) == 1 {
-		fmt.Fprintf(b, "\tret0 := %s.%s(", sourceCall, methodName)
-		for i, pName := range paramNames {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			if isVariadic && i == len(paramNames)-1 {
-				fmt.Fprintf(b, "%s...", pName)
-			} else {
-				if i < len(paramTypes) && isContextType(paramTypes[i]) {
-					fmt.Fprintf(b, "ctx.GoContext()")
-				} else {
-					fmt.Fprintf(b, "%s", pName)
-				}
-			}
-		}
-		fmt.Fprintf(b, ")\n")
-		if returnTypes[0].Kind() == reflect.Ptr && returnTypes[0].Elem().Kind() == reflect.Struct {
-			fmt.Fprintf(b, "\treturn data.NewClassValue(New%sClassFrom(ret0), ctx), nil\n", returnTypes[0].Elem().Name())
-		} else {
-			fmt.Fprintf(b, "\treturn data.NewAnyValue(ret0), nil\n")
-		}
-	} else {
-		// 通用：支持任意个返回值（>=2）
-		b.WriteString("\t")
-		for i := range returnTypes {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			fmt.Fprintf(b, "ret%d", i)
-		}
-		fmt.Fprintf(b, " := %s.%s(", sourceCall, methodName)
-		for i, pName := range paramNames {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			if isVariadic && i == len(paramNames)-1 {
-				fmt.Fprintf(b, "%s...", pName)
-			} else {
-				if i < len(paramTypes) && isContextType(paramTypes[i]) {
-					fmt.Fprintf(b, "ctx.GoContext()")
-				} else {
-					fmt.Fprintf(b, "%s", pName)
-				}
-			}
-		}
-		b.WriteString(")\n")
-		b.WriteString("\treturn data.NewArrayValue([]data.Value{")
-		for i := range returnTypes {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			fmt.Fprintf(b, "data.NewAnyValue(ret%d)", i)
-		}
-		b.WriteString("}), nil\n")
-	}
+	// 方法调用（context.Context 改为 ctx.GoContext()；末尾 error 转为脚本异常）
+	writeCallAndReturn(b, "h.source."+methodName, paramTypes, paramNames, isVariadic, returnTypes, result, srcPkgPath, config, fileCache)
 
 	b.WriteString("}\n\n")
 
@@ -196,8 +119,9 @@ func writeMethodImplementation(b *strings.Builder, typeName, methodName string,
 			if isContextType(paramTypes[i]) {
 				continue
 			}
-			pName := paramNames[i]
-			fmt.Fprintf(b, "\t\tnode.NewParameter(nil, \"%s\", %d, nil, nil),\n", pName, idx)
+			pName := scriptNames[i]
+			datExpr := getDataTypeExpr(paramTypes[i], config, fileCache)
+			fmt.Fprintf(b, "\t\tnode.NewParameter(nil, \"%s\", %d, nil, %s),\n", pName, idx, datExpr)
 			idx++
 		}
 		fmt.Fprintf(b, "\t}\n}\n")
@@ -208,8 +132,9 @@ func writeMethodImplementation(b *strings.Builder, typeName, methodName string,
 			if isContextType(paramTypes[i]) {
 				continue
 			}
-			pName := paramNames[i]
-			fmt.Fprintf(b, "\t\tnode.NewVariable(nil, \"%s\", %d, nil),\n", pName, idx)
+			pName := scriptNames[i]
+			datExpr := getDataTypeExpr(paramTypes[i], config, fileCache)
+			fmt.Fprintf(b, "\t\tnode.NewVariable(nil, \"%s\", %d, %s),\n", pName, idx, datExpr)
 			idx++
 		}
 		fmt.Fprintf(b, "\t}\n}\n")
@@ -220,7 +145,7 @@ func writeMethodImplementation(b *strings.Builder, typeName, methodName string,
 	}
 
 	// 返回类型
-	fmt.Fprintf(b, "func (h *%s%sMethod) GetReturnType() data.Types { return data.NewBaseType(\"void\") }\n", typeName, methodName)
+	fmt.Fprintf(b, "func (h *%s%sMethod) GetReturnType() data.Types { return %s }\n", typeName, methodName, getReturnTypeExpr(returnTypes, result, config, fileCache))
 }
 
 // analyzeMethodParams 分析方法参数
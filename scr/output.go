@@ -0,0 +1,155 @@
+package scr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Output 输出后端模块，生成文件统一经由 OutputFS 写出
+
+// OutputFS 生成文件的输出后端
+type OutputFS interface {
+	WriteFile(path string, data []byte) error
+	// Remove 删除文件，文件不存在时不报错
+	Remove(path string) error
+}
+
+// DiskFS 写入磁盘（默认后端）
+type DiskFS struct{}
+
+// WriteFile 自动创建目录后写入文件
+func (DiskFS) WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Remove 删除磁盘文件
+func (DiskFS) Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MemoryFS 写入内存，适合预览与测试
+type MemoryFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemoryFS 创建内存输出后端
+func NewMemoryFS() *MemoryFS {
+	return &MemoryFS{files: make(map[string][]byte)}
+}
+
+// WriteFile 保存文件内容，同一路径后写覆盖先写
+func (m *MemoryFS) WriteFile(path string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[filepath.Clean(path)] = append([]byte(nil), data...)
+	return nil
+}
+
+// Remove 删除已写入的文件
+func (m *MemoryFS) Remove(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, filepath.Clean(path))
+	return nil
+}
+
+// ReadFile 读取已写入的文件
+func (m *MemoryFS) ReadFile(path string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[filepath.Clean(path)]
+	return data, ok
+}
+
+// Paths 返回全部文件路径（已排序）
+func (m *MemoryFS) Paths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedKeys(m.files)
+}
+
+// Files 返回全部文件内容的副本
+func (m *MemoryFS) Files() map[string][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	files := make(map[string][]byte, len(m.files))
+	for p, data := range m.files {
+		files[p] = data
+	}
+	return files
+}
+
+// DryRunFS 不落盘，只记录计划写出的文件，结束后输出路径与大小
+type DryRunFS struct {
+	*MemoryFS
+
+	mu      sync.Mutex
+	removed []string
+}
+
+// NewDryRunFS 创建 dry-run 输出后端
+func NewDryRunFS() *DryRunFS {
+	return &DryRunFS{MemoryFS: NewMemoryFS()}
+}
+
+// Remove 记录计划删除的文件
+func (d *DryRunFS) Remove(path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.removed = append(d.removed, filepath.Clean(path))
+	return d.MemoryFS.Remove(path)
+}
+
+// Summary 输出计划写出的文件路径与大小，以及计划删除的文件
+func (d *DryRunFS) Summary(w io.Writer) {
+	paths := d.Paths()
+	total := 0
+	for _, p := range paths {
+		data, _ := d.ReadFile(p)
+		total += len(data)
+		fmt.Fprintf(w, "%s (%d 字节)\n", p, len(data))
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range d.removed {
+		fmt.Fprintf(w, "%s (删除)\n", p)
+	}
+	fmt.Fprintf(w, "共 %d 个文件，%d 字节，删除 %d 个（dry-run，未写入磁盘）\n", len(paths), total, len(d.removed))
+}
+
+// outputFS 返回配置的输出后端，未配置时写入磁盘
+func outputFS(config *Config) OutputFS {
+	if config != nil && config.Output != nil {
+		return config.Output
+	}
+	return DiskFS{}
+}
+
+// writeOutput 通过输出后端写文件，并记录到报告
+func (gc *GroupCache) writeOutput(path string, data []byte) error {
+	if err := outputFS(gc.Config).WriteFile(path, data); err != nil {
+		return err
+	}
+	gc.Report.AddFile(path)
+	return nil
+}
+
+// writeFiles 按路径顺序将文件集合写入输出后端
+func writeFiles(out OutputFS, files map[string][]byte) error {
+	for _, p := range sortedKeys(files) {
+		if err := out.WriteFile(p, files[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
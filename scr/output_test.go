@@ -0,0 +1,132 @@
+package scr
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// fsOp 对输出后端的一次写入或删除
+type fsOp struct {
+	remove bool
+	path   string
+	data   string
+}
+
+func applyOps(t *testing.T, out OutputFS, ops []fsOp) {
+	t.Helper()
+	for _, op := range ops {
+		var err error
+		if op.remove {
+			err = out.Remove(op.path)
+		} else {
+			err = out.WriteFile(op.path, []byte(op.data))
+		}
+		if err != nil {
+			t.Fatalf("%+v: %v", op, err)
+		}
+	}
+}
+
+func TestMemoryFS(t *testing.T) {
+	tests := []struct {
+		name  string
+		ops   []fsOp
+		files map[string]string
+	}{
+		{
+			name:  "写入并按路径排序",
+			ops:   []fsOp{{path: "out/b.go", data: "b"}, {path: "out/a.go", data: "a"}},
+			files: map[string]string{"out/a.go": "a", "out/b.go": "b"},
+		},
+		{
+			name:  "同一路径后写覆盖先写",
+			ops:   []fsOp{{path: "out/a.go", data: "old"}, {path: "out/./a.go", data: "new"}},
+			files: map[string]string{"out/a.go": "new"},
+		},
+		{
+			name: "删除已写入与不存在的文件",
+			ops: []fsOp{
+				{path: "out/a.go", data: "a"},
+				{path: "out/b.go", data: "b"},
+				{remove: true, path: "out/a.go"},
+				{remove: true, path: "out/missing.go"},
+			},
+			files: map[string]string{"out/b.go": "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemoryFS()
+			applyOps(t, m, tt.ops)
+			if got, want := m.Paths(), sortedKeys(tt.files); !reflect.DeepEqual(got, want) {
+				t.Errorf("Paths() = %q, 期望 %q", got, want)
+			}
+			for p, want := range tt.files {
+				got, ok := m.ReadFile(p)
+				if !ok || string(got) != want {
+					t.Errorf("ReadFile(%s) = %q, %v, 期望 %q", p, got, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryFSCopiesData(t *testing.T) {
+	m := NewMemoryFS()
+	data := []byte("abc")
+	if err := m.WriteFile("a.go", data); err != nil {
+		t.Fatal(err)
+	}
+	data[0] = 'x'
+	if got, _ := m.ReadFile("a.go"); string(got) != "abc" {
+		t.Errorf("ReadFile = %q, 写入后修改调用方切片不应影响已保存内容", got)
+	}
+}
+
+func TestDryRunFSSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     []fsOp
+		summary string
+	}{
+		{
+			name:    "无输出",
+			summary: "共 0 个文件，0 字节，删除 0 个（dry-run，未写入磁盘）\n",
+		},
+		{
+			name: "写入与删除",
+			ops: []fsOp{
+				{path: "out/demo/load.go", data: "package demo\n"},
+				{path: "out/demo/user_class.go", data: "class"},
+				{remove: true, path: "out/demo/old_class.go"},
+			},
+			summary: "out/demo/load.go (13 字节)\n" +
+				"out/demo/user_class.go (5 字节)\n" +
+				"out/demo/old_class.go (删除)\n" +
+				"共 2 个文件，18 字节，删除 1 个（dry-run，未写入磁盘）\n",
+		},
+		{
+			name: "删除已计划写出的文件",
+			ops: []fsOp{
+				{path: "out/demo/a.go", data: "a"},
+				{remove: true, path: "out/demo/a.go"},
+			},
+			summary: "out/demo/a.go (删除)\n" +
+				"共 0 个文件，0 字节，删除 1 个（dry-run，未写入磁盘）\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDryRunFS()
+			applyOps(t, d, tt.ops)
+			var buf bytes.Buffer
+			d.Summary(&buf)
+			if buf.String() != tt.summary {
+				t.Errorf("Summary() =\n%s期望\n%s", buf.String(), tt.summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package scr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParamNames 参数名模块
+//
+// reflect 无法获得参数名，这里通过 go/packages + go/ast 解析源码恢复声明的参数名与返回值名；
+// 源码不可用或参数未命名时，按 .cursor/rules/generator-output.mdc 中的启发式规则命名。
+
+// signatureNames 函数签名中声明的参数名与返回值名，未命名的位置为空字符串
+type signatureNames struct {
+	params  []string
+	results []string
+}
+
+// paramIndex 单个包内声明的参数名与返回值名
+type paramIndex struct {
+	// 函数名 -> 签名名称
+	funcs map[string]signatureNames
+	// 类型名 -> 方法名 -> 签名名称（结构体方法与接口方法）
+	methods map[string]map[string]signatureNames
+	// 类型名 -> 同包内嵌入的类型名，用于查找提升的方法
+	embeds map[string][]string
+	// 包作用域内声明的类型名，生成的结果类名须避开
+	types map[string]bool
+}
+
+// paramIndexEntry 包索引缓存项，保证每个包只解析一次
+type paramIndexEntry struct {
+	once  sync.Once
+	index *paramIndex
+}
+
+var paramIndexes = struct {
+	mu      sync.Mutex
+	entries map[string]*paramIndexEntry
+}{entries: make(map[string]*paramIndexEntry)}
+
+// sourceParamIndex 返回包的参数名索引，源码不可用时返回 nil
+func sourceParamIndex(pkgPath string) *paramIndex {
+	if pkgPath == "" {
+		return nil
+	}
+	paramIndexes.mu.Lock()
+	entry, ok := paramIndexes.entries[pkgPath]
+	if !ok {
+		entry = &paramIndexEntry{}
+		paramIndexes.entries[pkgPath] = entry
+	}
+	paramIndexes.mu.Unlock()
+
+	entry.once.Do(func() {
+		// 解析失败时回退启发式命名，不影响生成
+		entry.index, _ = loadParamIndex(pkgPath)
+	})
+	return entry.index
+}
+
+// loadParamIndex 解析包源码（仅语法，不做类型检查），收集参数名
+func loadParamIndex(pkgPath string) (*paramIndex, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax}
+	pkgs, err := packages.Load(cfg, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 || len(pkgs[0].Syntax) == 0 {
+		return nil, fmt.Errorf("包 %s 没有可解析的源码", pkgPath)
+	}
+
+	idx := &paramIndex{
+		funcs:   make(map[string]signatureNames),
+		methods: make(map[string]map[string]signatureNames),
+		embeds:  make(map[string][]string),
+		types:   make(map[string]bool),
+	}
+	for _, file := range pkgs[0].Syntax {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				idx.addFuncDecl(d)
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						idx.addTypeSpec(ts)
+					}
+				}
+			}
+		}
+	}
+	return idx, nil
+}
+
+// addFuncDecl 记录函数或方法的参数名与返回值名
+func (idx *paramIndex) addFuncDecl(d *ast.FuncDecl) {
+	names := funcTypeNames(d.Type)
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		idx.funcs[d.Name.Name] = names
+		return
+	}
+	if typeName := baseTypeName(d.Recv.List[0].Type); typeName != "" {
+		idx.addMethod(typeName, d.Name.Name, names)
+	}
+}
+
+// addTypeSpec 记录接口方法的签名名称，以及结构体/接口的嵌入关系
+func (idx *paramIndex) addTypeSpec(ts *ast.TypeSpec) {
+	typeName := ts.Name.Name
+	idx.types[typeName] = true
+	switch t := ts.Type.(type) {
+	case *ast.InterfaceType:
+		for _, field := range t.Methods.List {
+			if ft, ok := field.Type.(*ast.FuncType); ok {
+				for _, name := range field.Names {
+					idx.addMethod(typeName, name.Name, funcTypeNames(ft))
+				}
+				continue
+			}
+			if embedded := baseTypeName(field.Type); embedded != "" {
+				idx.embeds[typeName] = append(idx.embeds[typeName], embedded)
+			}
+		}
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if len(field.Names) > 0 {
+				continue
+			}
+			if embedded := baseTypeName(field.Type); embedded != "" {
+				idx.embeds[typeName] = append(idx.embeds[typeName], embedded)
+			}
+		}
+	}
+}
+
+func (idx *paramIndex) addMethod(typeName, methodName string, names signatureNames) {
+	if idx.methods[typeName] == nil {
+		idx.methods[typeName] = make(map[string]signatureNames)
+	}
+	idx.methods[typeName][methodName] = names
+}
+
+// funcSignature 返回函数声明的参数名与返回值名
+func (idx *paramIndex) funcSignature(name string) (signatureNames, bool) {
+	if idx == nil {
+		return signatureNames{}, false
+	}
+	names, ok := idx.funcs[name]
+	return names, ok
+}
+
+// methodSignature 返回方法声明的参数名与返回值名，找不到时沿同包嵌入类型查找提升的方法
+func (idx *paramIndex) methodSignature(typeName, methodName string) (signatureNames, bool) {
+	if idx == nil {
+		return signatureNames{}, false
+	}
+	visited := map[string]bool{}
+	queue := []string{typeName}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+		if names, ok := idx.methods[name][methodName]; ok {
+			return names, true
+		}
+		queue = append(queue, idx.embeds[name]...)
+	}
+	return signatureNames{}, false
+}
+
+// declaresType 包作用域内是否声明了名为 name 的类型
+func (idx *paramIndex) declaresType(name string) bool {
+	return idx != nil && idx.types[name]
+}
+
+// funcTypeNames 提取函数类型中的参数名与返回值名
+func funcTypeNames(ft *ast.FuncType) signatureNames {
+	return signatureNames{params: fieldListNames(ft.Params), results: fieldListNames(ft.Results)}
+}
+
+// fieldListNames 展开参数列表中的名称，未命名参数为空字符串
+func fieldListNames(fl *ast.FieldList) []string {
+	if fl == nil {
+		return nil
+	}
+	var names []string
+	for _, field := range fl.List {
+		if len(field.Names) == 0 {
+			names = append(names, "")
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// baseTypeName 取类型表达式中同包的类型名：T、*T、T[K, V] 等；其他包的类型返回空
+func baseTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return baseTypeName(e.X)
+	case *ast.IndexExpr:
+		return baseTypeName(e.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(e.X)
+	case *ast.ParenExpr:
+		return baseTypeName(e.X)
+	}
+	return ""
+}
+
+// generatedLocalPattern 生成代码中使用的局部变量，参数名不能与之冲突
+var generatedLocalPattern = regexp.MustCompile(`^(ctx|h|s|v|p|ok|err|av|avv|vv|ret[0-9]+)$`)
+
+// resolveParamNames 确定参数名：源码声明的名称优先，其余按启发式命名
+// 返回脚本可见的参数名与生成代码中的局部变量名：脚本中保持声明的名称，
+// 局部变量与局部变量、导入名、预声明标识符、Go 关键字冲突时才追加 Arg 后缀
+// （启发式命名可能得到 type、func 等关键字，如 Type、*Func 类型的参数）
+//
+// 参数说明：
+// - sourceNames: 源码中的参数名（可为 nil，长度不一致时整体忽略）
+// - paramTypes: 参数类型（不含接收者）
+// - callName: 函数或方法名，用于启发式规则
+// - isVariadic: 最后一个参数是否为可变参数
+// - fileCache: 文件缓存，用于收集导入名
+func resolveParamNames(sourceNames []string, paramTypes []reflect.Type, callName string, isVariadic bool, fileCache *FileCache) (scriptNames, goNames []string) {
+	if len(sourceNames) != len(paramTypes) {
+		sourceNames = nil
+	}
+
+	reserved := map[string]bool{}
+	for pkgPath, alias := range fileCache.GetImports() {
+		if alias == "" {
+			alias = path.Base(pkgPath)
+		}
+		reserved[alias] = true
+	}
+
+	usedScript := map[string]bool{}
+	usedGo := map[string]bool{}
+	scriptNames = make([]string, len(paramTypes))
+	goNames = make([]string, len(paramTypes))
+	for i, t := range paramTypes {
+		name := ""
+		if sourceNames != nil {
+			name = sourceNames[i]
+		}
+		if name == "" || name == "_" {
+			name = heuristicParamName(t, callName, i, isVariadic && i == len(paramTypes)-1)
+		}
+		for base, n := name, 1; usedScript[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		usedScript[name] = true
+		scriptNames[i] = name
+
+		if reserved[name] || generatedLocalPattern.MatchString(name) || types.Universe.Lookup(name) != nil || token.IsKeyword(name) {
+			name += "Arg"
+		}
+		for base, n := name, 1; usedGo[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		usedGo[name] = true
+		goNames[i] = name
+	}
+	return scriptNames, goNames
+}
+
+// resolveResultNames 确定多返回值结果类的属性名：源码声明的返回值名优先，其余按类型推导
+// 属性名只作为脚本中的属性键，不需要避开 Go 标识符，仅保证唯一
+func resolveResultNames(sourceNames []string, values []reflect.Type) []string {
+	if len(sourceNames) < len(values) {
+		sourceNames = nil
+	}
+
+	used := map[string]bool{}
+	names := make([]string, len(values))
+	for i, t := range values {
+		name := ""
+		if sourceNames != nil {
+			name = sourceNames[i]
+		}
+		if name == "" || name == "_" {
+			name = heuristicResultName(t, i, i == len(values)-1)
+		}
+		for base, n := name, 1; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// heuristicResultName 按类型推导返回值名：末尾的 bool -> ok，具名类型 Xxx -> xxx，其余 -> valueN
+func heuristicResultName(t reflect.Type, index int, last bool) string {
+	elem := t
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	switch {
+	case last && t.Kind() == reflect.Bool:
+		return "ok"
+	case elem.PkgPath() != "" && IsExportedType(elem.Name()):
+		return lowerFirst(typeDeclName(elem))
+	}
+	return fmt.Sprintf("value%d", index)
+}
+
+// heuristicParamName 按类型与上下文推导参数名
+//
+// 规则（依次匹配）：
+// - time.Duration 等名为 Duration 的类型 -> d
+// - *XxxOptions -> opts
+// - 切片或可变参数 -> args
+// - 名称含 query/prepare/exec 的函数中的 string 参数 -> query
+// - set/setmax 系函数中的整型参数 -> n
+// - 其他具名类型（指针解引用后）Xxx -> xxx
+// - 其余 -> paramN
+func heuristicParamName(t reflect.Type, callName string, index int, variadic bool) string {
+	isPtr := t.Kind() == reflect.Ptr
+	elem := t
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	lowerCall := strings.ToLower(callName)
+
+	switch {
+	case elem.Name() == "Duration":
+		return "d"
+	case isPtr && strings.HasSuffix(elem.Name(), "Options"):
+		return "opts"
+	case variadic || t.Kind() == reflect.Slice:
+		return "args"
+	case t.Kind() == reflect.String && containsAny(lowerCall, "query", "prepare", "exec"):
+		return "query"
+	case isIntKind(t.Kind()) && strings.HasPrefix(lowerCall, "set"):
+		return "n"
+	case elem.PkgPath() != "" && IsExportedType(elem.Name()):
+		return lowerFirst(typeDeclName(elem))
+	}
+	return fmt.Sprintf("param%d", index)
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package scr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Type、Func、Doer 测试用类型：启发式命名会得到 Go 关键字 type、func
+type (
+	Type struct{}
+	Func func()
+	Doer interface{ Do(Type) error }
+)
+
+// UseKeywords 未命名参数的函数，参数名由启发式规则推导
+func UseKeywords(Type, Func) {}
+
+func TestResolveParamNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		sourceNames []string
+		paramTypes  []reflect.Type
+		imports     []string
+		script      []string
+		goNames     []string
+	}{
+		{
+			name:       "关键字",
+			paramTypes: []reflect.Type{reflect.TypeFor[Type](), reflect.TypeFor[*Func]()},
+			script:     []string{"type", "func"},
+			goNames:    []string{"typeArg", "funcArg"},
+		},
+		{
+			name:        "声明的关键字以外名称保持不变",
+			sourceNames: []string{"name", "range_"},
+			paramTypes:  []reflect.Type{reflect.TypeFor[string](), reflect.TypeFor[int]()},
+			script:      []string{"name", "range_"},
+			goNames:     []string{"name", "range_"},
+		},
+		{
+			name:        "导入名",
+			sourceNames: []string{"time", "utils"},
+			paramTypes:  []reflect.Type{reflect.TypeFor[time.Time](), reflect.TypeFor[string]()},
+			imports:     []string{"time", "github.com/php-any/generator/utils"},
+			script:      []string{"time", "utils"},
+			goNames:     []string{"timeArg", "utilsArg"},
+		},
+		{
+			name:        "生成代码局部变量与预声明标识符",
+			sourceNames: []string{"ctx", "len", "err"},
+			paramTypes:  []reflect.Type{reflect.TypeFor[int](), reflect.TypeFor[int](), reflect.TypeFor[string]()},
+			script:      []string{"ctx", "len", "err"},
+			goNames:     []string{"ctxArg", "lenArg", "errArg"},
+		},
+		{
+			name:        "重名",
+			sourceNames: []string{"", ""},
+			paramTypes:  []reflect.Type{reflect.TypeFor[Type](), reflect.TypeFor[Type]()},
+			script:      []string{"type", "type1"},
+			goNames:     []string{"typeArg", "type1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileCache := NewFileCache()
+			for _, p := range tt.imports {
+				fileCache.AddImport(p, "")
+			}
+			script, goNames := resolveParamNames(tt.sourceNames, tt.paramTypes, "Use", false, fileCache)
+			if !reflect.DeepEqual(script, tt.script) || !reflect.DeepEqual(goNames, tt.goNames) {
+				t.Errorf("resolveParamNames() = %q, %q, 期望 %q, %q", script, goNames, tt.script, tt.goNames)
+			}
+		})
+	}
+}
+
+// TestGenerateKeywordParamNames 参数名推导为关键字时生成的代码仍能通过格式化（语法正确）
+func TestGenerateKeywordParamNames(t *testing.T) {
+	for _, value := range []any{UseKeywords, (*Doer)(nil)} {
+		out := NewMemoryFS()
+		report, err := GenerateFromAny(value, &Config{OutputRoot: "out", NamePrefix: "test", Output: out})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.HasDiagnostics() {
+			t.Fatalf("%T: %s", value, report)
+		}
+		found := false
+		for _, data := range out.Files() {
+			found = found || strings.Contains(string(data), "typeArg")
+		}
+		if !found {
+			t.Errorf("%T: 生成代码中没有 typeArg", value)
+		}
+	}
+}
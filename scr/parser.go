@@ -2,48 +2,98 @@ package scr
 
 import (
 	"errors"
-	"os"
-	"path/filepath"
+	"fmt"
 	"reflect"
 )
 
-func GenerateFromAny(a any, config *Config) error {
-	t := reflect.TypeOf(a)
-	if t == nil {
-		return errors.New("输入为 nil，不支持")
+// GenerateFromAny 为值对应的类型或函数生成绑定
+// 单个符号无法生成时记录到报告并继续，仅输入本身无效时返回 error
+func GenerateFromAny(a any, config *Config) (*Report, error) {
+	return GenerateAll([]any{a}, config)
+}
+
+// GenerateAll 并行为多个值生成绑定，全部完成后统一写出 load.go
+// 单个符号无法生成时记录到报告并继续，仅输入本身无效或写出 load.go 失败时返回 error
+func GenerateAll(values []any, config *Config) (*Report, error) {
+	types := make([]reflect.Type, len(values))
+	for i, a := range values {
+		t := reflect.TypeOf(a)
+		// 泛型函数实例按其函数值生成，GenericFunc 本身作为 originalValue 传递
+		if g, ok := a.(GenericFunc); ok {
+			t = reflect.TypeOf(g.Value)
+		}
+		if t == nil {
+			return nil, errors.New("输入为 nil，不支持")
+		}
+		// (*Iface)(nil) 用于传递接口类型，按接口本身生成
+		if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+			t = t.Elem()
+		}
+		types[i] = t
 	}
+
 	cache := NewGroupCache(config)
-	return generateFromType(t, cache, a)
+	for i, t := range types {
+		cache.Report.addPackage(valuePackagePath(t, values[i]))
+		cache.schedule(t, values[i])
+	}
+	cache.wait()
+
+	if err := cache.emitLoadFiles(); err != nil {
+		return nil, fmt.Errorf("生成 load.go 失败: %w", err)
+	}
+	// 并行完成顺序不固定，排序后报告保持稳定
+	cache.Report.sort()
+	return cache.Report, nil
+}
+
+// valuePackagePath 返回顶层符号所在的源包路径
+func valuePackagePath(t reflect.Type, originalValue any) string {
+	if t.Kind() == reflect.Func {
+		return getFunctionPackagePath(t, originalValue)
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath()
 }
 
-func generateFromType(t reflect.Type, cache *GroupCache, originalValue any) error {
+// generateDependency 提交依赖类型的生成任务，失败时记录诊断而不中断当前符号
+// 黑名单包中的类型在生成代码中以 data.AnyValue 表示，不生成绑定
+func generateDependency(t reflect.Type, cache *GroupCache) {
+	if isBlacklistedPackage(valuePackagePath(t, nil), cache.Config) {
+		return
+	}
+	cache.schedule(t, nil)
+}
+
+func generateFromType(t reflect.Type, cache *GroupCache, originalValue any) (err error) {
+	// 异常签名等导致的 panic 转为诊断，不影响其他符号
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("生成时发生异常: %v", r)
+		}
+	}()
+
 	// 检查深度限制
 	if cache.Config != nil && cache.Config.MaxDepth > 0 && cache.CurrentDepth >= cache.Config.MaxDepth {
 		return nil
 	}
 
 	// 检查缓存，防止重复生成和死循环
-	typeKey := t.String()
-	if cache.IsTypeGenerated(typeKey) {
+	// 检查与标记须原子完成，避免多个 worker 重复生成同一类型
+	if !cache.tryMarkTypeGenerated(generationKey(t, originalValue)) {
 		return nil
 	}
 
-	// 标记为已生成
-	cache.MarkTypeGenerated(typeKey)
-
 	// 提前检查需要生成的文件名，是否是直接替换的
 	// 根据类型信息生成预期的文件名
 	expectedFile := generateExpectedFileName(t, cache)
 	if expectedFile != "" {
 		if replacementFile, ok := cache.Config.FixedReplace[expectedFile]; ok {
-			// 确保目标目录存在
-			dir := filepath.Dir(expectedFile)
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				panic(err)
-			}
 			// 复制替换文件到目标位置
-			if err := copyFile(replacementFile, expectedFile); err != nil {
-				panic(err)
+			if err := copyFile(replacementFile, expectedFile, cache); err != nil {
+				return fmt.Errorf("复制替换文件 %s 失败: %w", replacementFile, err)
 			}
 			return nil // 文件已替换，不需要继续生成
 		}
@@ -59,16 +109,34 @@ func generateFromType(t reflect.Type, cache *GroupCache, originalValue any) erro
 		return nil
 	}
 
+	// 类型本身或函数签名引用了 internal 包、未导出的类型时，生成的代码无法编译
+	if err := checkImportable(t); err != nil {
+		return err
+	}
+
 	switch parseTypes(t) {
 	case "class":
 		return buildClass(t, cache, cache.Config)
 	case "func":
+		// 作为依赖出现的函数类型（返回值、字段）没有函数值可调用，不生成函数绑定
+		if originalValue == nil {
+			return nil
+		}
 		return buildFunc(t, cache, originalValue)
 	}
 
 	return nil
 }
 
+// generationKey 返回去重使用的键
+// 签名相同的不同函数类型字符串相同，函数值按真实函数全名区分
+func generationKey(t reflect.Type, originalValue any) string {
+	if t.Kind() == reflect.Func && originalValue != nil {
+		return diagnosticSymbol(t, originalValue)
+	}
+	return t.String()
+}
+
 // IsExportedType 检查类型名是否为导出的（大写开头）
 func IsExportedType(typeName string) bool {
 	if typeName == "" {
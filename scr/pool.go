@@ -0,0 +1,68 @@
+package scr
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// Pool 并行生成模块
+//
+// 每个待生成的类型或函数作为一个任务提交到 worker 池，依赖类型在分析过程中继续提交，
+// 任务之间互不等待，因此不会因池满而死锁。load.go 在全部任务结束后统一写出。
+
+// workerPool 限制并发数的任务池
+type workerPool struct {
+	wg  sync.WaitGroup
+	sem chan struct{}
+}
+
+// newWorkerPool 创建并发数为 workers 的任务池
+func newWorkerPool(workers int) *workerPool {
+	return &workerPool{sem: make(chan struct{}, workers)}
+}
+
+// configWorkers 返回配置的并发数，未配置时使用 GOMAXPROCS
+func configWorkers(config *Config) int {
+	if config != nil && config.Workers > 0 {
+		return config.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// schedule 提交一个生成任务，失败时记录诊断
+func (gc *GroupCache) schedule(t reflect.Type, originalValue any) {
+	gc.pool.wg.Add(1)
+	go func() {
+		defer gc.pool.wg.Done()
+		gc.pool.sem <- struct{}{}
+		defer func() { <-gc.pool.sem }()
+
+		if err := generateFromType(t, gc, originalValue); err != nil {
+			gc.Report.Add(diagnosticSymbol(t, originalValue), err)
+		}
+	}()
+}
+
+// wait 等待全部任务（包括任务中提交的依赖任务）完成
+func (gc *GroupCache) wait() {
+	gc.pool.wg.Wait()
+}
+
+// ownedOutputPackages 返回本次请求生成的源包对应的输出子包（不含仅作为依赖生成的子包）
+func (gc *GroupCache) ownedOutputPackages() map[string]bool {
+	gc.Report.mu.Lock()
+	defer gc.Report.mu.Unlock()
+	return ownedPackages(gc.Config, gc.Report.Packages)
+}
+
+// emitLoadFiles 为本次生成涉及的每个输出子包写出 load.go
+func (gc *GroupCache) emitLoadFiles() error {
+	owned := gc.ownedOutputPackages()
+	for _, pkgName := range gc.listLoadPackages() {
+		if err := emitLoadFile(pkgName, owned, gc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package scr
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/php-any/generator/demo"
+)
+
+// demoValues demo 包中相互引用的符号，用于并行生成测试
+var demoValues = []any{
+	demo.NewUser, demo.NewConfig, demo.ValidateUser, demo.ProcessUsers, demo.GetUserByID,
+	demo.CreateEvent, demo.CountActiveUsers, demo.StreamEvents, demo.NewEventQueue,
+	demo.MergeMetadata, demo.PartitionUsers, demo.IndexUsers, demo.DescribeItem, demo.Parse,
+	(*demo.User)(nil), (*demo.AdminUser)(nil), (*demo.Config)(nil), (*demo.Event)(nil),
+	(*demo.Node)(nil), (*demo.UserService)(nil), (*demo.Describer)(nil),
+}
+
+// TestGenerateAllParallel 不同并发数下生成结果完全一致，同一类型只生成一次
+func TestGenerateAllParallel(t *testing.T) {
+	generate := func(workers int) (map[string][]byte, *Report) {
+		t.Helper()
+		out := NewMemoryFS()
+		config := &Config{OutputRoot: "out", NamePrefix: "demo", Workers: workers, Output: out}
+		report, err := GenerateAll(demoValues, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.Files(), report
+	}
+
+	serialFiles, serialReport := generate(1)
+	if len(serialFiles) == 0 {
+		t.Fatal("没有生成文件")
+	}
+	for i := 0; i < 3; i++ {
+		files, report := generate(16)
+		if !reflect.DeepEqual(sortedKeys(files), sortedKeys(serialFiles)) {
+			t.Fatalf("并行生成的文件 = %q, 期望 %q", sortedKeys(files), sortedKeys(serialFiles))
+		}
+		for p, data := range files {
+			if string(data) != string(serialFiles[p]) {
+				t.Errorf("并行生成的 %s 与串行结果不同", p)
+			}
+		}
+		if !reflect.DeepEqual(report.Files, serialReport.Files) || !reflect.DeepEqual(report.Diagnostics, serialReport.Diagnostics) {
+			t.Errorf("并行生成的报告与串行结果不同:\n%v\n%v", report, serialReport)
+		}
+	}
+}
+
+// TestGlobalCacheConcurrentRegister 并发注册类与函数不丢失、不重复
+func TestGlobalCacheConcurrentRegister(t *testing.T) {
+	const pkgName = "concurrentregister"
+	var wg sync.WaitGroup
+	var wantClasses, wantFunctions []string
+	for i := 0; i < 50; i++ {
+		class, function := fmt.Sprintf("Class%02d", i), fmt.Sprintf("Func%02d", i)
+		wantClasses = append(wantClasses, class)
+		wantFunctions = append(wantFunctions, function)
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				globalCache.RegisterClass(pkgName, class)
+				globalCache.RegisterFunction(pkgName, function)
+			}()
+		}
+	}
+	wg.Wait()
+
+	classes, functions := globalCache.ListRegistered(pkgName)
+	if !reflect.DeepEqual(classes, wantClasses) || !reflect.DeepEqual(functions, wantFunctions) {
+		t.Errorf("ListRegistered = %q, %q", classes, functions)
+	}
+}
@@ -0,0 +1,150 @@
+package scr
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Report 生成报告模块，收集无法生成的符号及原因，不中断其他条目的生成
+
+// Diagnostic 单个符号的生成诊断
+type Diagnostic struct {
+	// 类型名、函数名或 类型.方法 名
+	Symbol string
+	// 无法生成的原因
+	Reason string
+}
+
+func (d Diagnostic) String() string {
+	return d.Symbol + ": " + d.Reason
+}
+
+// Report 一次生成的汇总报告
+type Report struct {
+	Diagnostics []Diagnostic
+	// 本次写出（或计划写出）的文件路径，去重，生成结束后按路径排序
+	Files []string
+	// 本次请求生成的源包导入路径（顶层符号所在的包，不含依赖），去重后排序
+	// SyncManifest 只清理这些包的输出子包
+	Packages []string
+
+	fileSet map[string]bool
+	// 并行生成时多个 worker 同时写入
+	mu sync.Mutex
+}
+
+// Add 记录符号的诊断，err 为 nil 时忽略
+func (r *Report) Add(symbol string, err error) {
+	if err == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{Symbol: symbol, Reason: err.Error()})
+}
+
+// AddFile 记录写出的文件，重复路径只记录一次
+func (r *Report) AddFile(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addFile(path)
+}
+
+// addFile 记录写出的文件，调用方需持有锁
+func (r *Report) addFile(path string) {
+	if r.fileSet == nil {
+		r.fileSet = make(map[string]bool, len(r.Files))
+		for _, p := range r.Files {
+			r.fileSet[p] = true
+		}
+	}
+	if r.fileSet[path] {
+		return
+	}
+	r.fileSet[path] = true
+	r.Files = append(r.Files, path)
+}
+
+// addPackage 记录请求生成的源包，重复路径只记录一次
+func (r *Report) addPackage(pkgPath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mergePackages([]string{pkgPath})
+}
+
+// mergePackages 合并源包路径，调用方需持有锁
+func (r *Report) mergePackages(paths []string) {
+	for _, p := range paths {
+		if p != "" && !slices.Contains(r.Packages, p) {
+			r.Packages = append(r.Packages, p)
+		}
+	}
+}
+
+// Merge 合并另一份报告
+func (r *Report) Merge(other *Report) {
+	if other == nil || other == r {
+		return
+	}
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Diagnostics = append(r.Diagnostics, other.Diagnostics...)
+	for _, p := range other.Files {
+		r.addFile(p)
+	}
+	r.mergePackages(other.Packages)
+	sort.Strings(r.Packages)
+}
+
+// sort 按符号与路径排序，消除并行生成带来的顺序差异
+func (r *Report) sort() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.SliceStable(r.Diagnostics, func(i, j int) bool {
+		return r.Diagnostics[i].Symbol < r.Diagnostics[j].Symbol
+	})
+	sort.Strings(r.Files)
+	sort.Strings(r.Packages)
+}
+
+// HasDiagnostics 是否存在诊断
+func (r *Report) HasDiagnostics() bool {
+	return r != nil && len(r.Diagnostics) > 0
+}
+
+func (r *Report) String() string {
+	if !r.HasDiagnostics() {
+		return "生成完成，无诊断"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "以下 %d 个符号未能生成:", len(r.Diagnostics))
+	for _, d := range r.Diagnostics {
+		b.WriteString("\n  - ")
+		b.WriteString(d.String())
+	}
+	return b.String()
+}
+
+// diagnosticSymbol 返回诊断中使用的符号名
+// 函数优先使用真实函数全名，类型去掉指针后使用 包名.类型名
+func diagnosticSymbol(t reflect.Type, originalValue any) string {
+	if g, ok := originalValue.(GenericFunc); ok {
+		return g.symbol()
+	}
+	if t.Kind() == reflect.Func && originalValue != nil {
+		if f := runtime.FuncForPC(reflect.ValueOf(originalValue).Pointer()); f != nil {
+			return f.Name()
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.String()
+}
@@ -0,0 +1,127 @@
+package scr
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ResultTemplates 多返回值结果类模块
+//
+// 两个及以上非 error 返回值时，为每个函数/方法生成一个结果类，
+// 属性名取自源码中的返回值名，脚本中可写 $r->count 而不是 $r[1]。
+// 结果类名为 <函数名>Result；与包内声明的类型同名时（如 Parse 与 ParseResult）
+// 依次尝试 <函数名>Result2、<函数名>Result3……避免与该类型生成的类重复声明。
+
+// resultClass 多返回值结果类描述
+type resultClass struct {
+	// Name 结果类名（不含 Class 后缀），如 EventGetDataResult
+	Name string
+	// ScriptName 脚本中的完整类名，如 demo\EventGetDataResult
+	ScriptName string
+	// Props 属性名，与 Values 一一对应
+	Props []string
+	// Values 返回值类型（不含末尾 error）
+	Values []reflect.Type
+}
+
+// newResultClass 构建结果类描述，非 error 返回值少于两个时返回 nil
+//
+// 参数说明：
+// - baseName: 函数名，或 类型名+方法名
+// - namespace: 脚本命名空间
+// - returnTypes: 全部返回值类型
+// - sourceNames: 源码中的返回值名（可为 nil）
+// - scope: 源包索引，用于避开包内声明的类型名（可为 nil）
+func newResultClass(baseName, namespace string, returnTypes []reflect.Type, sourceNames []string, scope *paramIndex) *resultClass {
+	values, _ := splitErrorReturn(returnTypes)
+	if len(values) < 2 {
+		return nil
+	}
+	name := baseName + "Result"
+	for n := 2; scope.declaresType(name); n++ {
+		name = fmt.Sprintf("%sResult%d", baseName, n)
+	}
+	return &resultClass{
+		Name:       name,
+		ScriptName: namespace + "\\" + name,
+		Props:      resolveResultNames(sourceNames, values),
+		Values:     values,
+	}
+}
+
+// generateResultClassFile 生成结果类文件并注册到 load.go
+// fileStem 与对应的函数/方法文件一致，如 event_getdata -> event_getdata_result.go；
+// 类型文件以 _class.go 结尾，结果类改名后文件名无需变化
+func generateResultClassFile(rc *resultClass, fileStem, pkgName string, cache *GroupCache) error {
+	outDir := filepath.Join(cache.Config.OutputRoot, pkgName)
+	resultFile := filepath.Join(outDir, fileStem+"_result.go")
+
+	if err := emitFile(resultFile, pkgName, buildResultClassFileBody(rc), cache); err != nil {
+		return err
+	}
+	globalCache.RegisterClass(pkgName, rc.Name)
+	return nil
+}
+
+// buildResultClassFileBody 构建结果类文件内容
+// 属性值在调用处已封装为 data.Value，结果类只负责按名称暴露
+func buildResultClassFileBody(rc *resultClass) string {
+	b := &strings.Builder{}
+	name := rc.Name
+
+	b.WriteString("import (\n")
+	b.WriteString("\t\"github.com/php-any/origami/data\"\n")
+	b.WriteString("\t\"github.com/php-any/origami/node\"\n")
+	b.WriteString(")\n\n")
+
+	// 结构体与构造函数
+	fmt.Fprintf(b, "type %sClass struct {\n", name)
+	b.WriteString("\tnode.Node\n")
+	b.WriteString("\tvalues []data.Value\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func New%sClass() data.ClassStmt {\n", name)
+	fmt.Fprintf(b, "\tvalues := make([]data.Value, %d)\n", len(rc.Props))
+	b.WriteString("\tfor i := range values {\n\t\tvalues[i] = data.NewNullValue()\n\t}\n")
+	fmt.Fprintf(b, "\treturn &%sClass{values: values}\n", name)
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func New%sClassFrom(values ...data.Value) data.ClassStmt {\n", name)
+	fmt.Fprintf(b, "\treturn &%sClass{values: values}\n", name)
+	b.WriteString("}\n\n")
+
+	// 类接口实现
+	fmt.Fprintf(b, "func (s *%sClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {\n", name)
+	fmt.Fprintf(b, "\treturn data.NewProxyValue(New%sClass(), ctx.CreateBaseContext()), nil\n", name)
+	b.WriteString("}\n\n")
+	fmt.Fprintf(b, "func (s *%sClass) GetName() string { return %q }\n", name, rc.ScriptName)
+	fmt.Fprintf(b, "func (s *%sClass) GetExtend() *string { return nil }\n", name)
+	fmt.Fprintf(b, "func (s *%sClass) GetImplements() []string { return nil }\n", name)
+	fmt.Fprintf(b, "func (s *%sClass) AsString() string { return \"%s{}\" }\n", name, name)
+	fmt.Fprintf(b, "func (s *%sClass) GetMethod(name string) (data.Method, bool) { return nil, false }\n", name)
+	fmt.Fprintf(b, "func (s *%sClass) GetMethods() []data.Method { return []data.Method{} }\n", name)
+	fmt.Fprintf(b, "func (s *%sClass) GetConstruct() data.Method { return nil }\n\n", name)
+
+	// 属性
+	fmt.Fprintf(b, "func (s *%sClass) GetProperty(name string) (data.Property, bool) {\n", name)
+	b.WriteString("\tswitch name {\n")
+	for i, prop := range rc.Props {
+		fmt.Fprintf(b, "\tcase %q:\n", prop)
+		fmt.Fprintf(b, "\t\treturn node.NewProperty(nil, %q, \"public\", true, s.values[%d]), true\n", prop, i)
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn nil, false\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func (s *%sClass) GetProperties() map[string]data.Property {\n", name)
+	b.WriteString("\treturn map[string]data.Property{\n")
+	for i, prop := range rc.Props {
+		fmt.Fprintf(b, "\t\t%q: node.NewProperty(nil, %q, \"public\", true, s.values[%d]),\n", prop, prop, i)
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
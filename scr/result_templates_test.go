@@ -0,0 +1,33 @@
+package scr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewResultClassName(t *testing.T) {
+	returns := []reflect.Type{reflect.TypeFor[int](), reflect.TypeFor[bool](), reflect.TypeFor[error]()}
+	tests := []struct {
+		name  string
+		scope *paramIndex
+		want  string
+	}{
+		{"无源码索引", nil, "ParseResult"},
+		{"包内没有同名类型", &paramIndex{types: map[string]bool{"Parser": true}}, "ParseResult"},
+		{"与包内类型同名", &paramIndex{types: map[string]bool{"ParseResult": true}}, "ParseResult2"},
+		{"依次避开", &paramIndex{types: map[string]bool{"ParseResult": true, "ParseResult2": true}}, "ParseResult3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := newResultClass("Parse", "demo", returns, nil, tt.scope)
+			if rc.Name != tt.want || rc.ScriptName != "demo\\"+tt.want {
+				t.Errorf("结果类 = %s (%s), 期望 %s", rc.Name, rc.ScriptName, tt.want)
+			}
+		})
+	}
+
+	if rc := newResultClass("Parse", "demo", returns[1:], nil, nil); rc != nil {
+		t.Errorf("单个非 error 返回值不应生成结果类: %+v", rc)
+	}
+}
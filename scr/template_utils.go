@@ -13,16 +13,18 @@ func getTypeString(t reflect.Type, fileCache *FileCache) string {
 	}
 
 	// 具名类型（含包路径与类型名）优先返回"包名.类型名"，以保留别名/定义类型
+	// 泛型实例的类型实参同样改写为导入别名
 	if t.PkgPath() != "" && t.Name() != "" {
+		name := genericTypeName(t, fileCache)
 		// 优先使用 FileCache 中的别名
 		if fileCache != nil {
 			if alias, exists := fileCache.Imports[t.PkgPath()]; exists && alias != "" {
-				return alias + "." + t.Name()
+				return alias + "." + name
 			}
 		}
 		// 回退到包名
 		pkgName := pkgBaseName(t.PkgPath())
-		return pkgName + "." + t.Name()
+		return pkgName + "." + name
 	}
 
 	switch t.Kind() {
@@ -35,7 +37,18 @@ func getTypeString(t reflect.Type, fileCache *FileCache) string {
 	case reflect.Map:
 		return fmt.Sprintf("map[%s]%s", getTypeString(t.Key(), fileCache), getTypeString(t.Elem(), fileCache))
 	case reflect.Chan:
-		return "chan " + getTypeString(t.Elem(), fileCache)
+		elem := getTypeString(t.Elem(), fileCache)
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + elem
+		case reflect.SendDir:
+			return "chan<- " + elem
+		}
+		// chan (<-chan T) 需要括号，否则会被解析为 chan<- (chan T)
+		if t.Elem().Kind() == reflect.Chan && t.Elem().ChanDir() == reflect.RecvDir {
+			elem = "(" + elem + ")"
+		}
+		return "chan " + elem
 	case reflect.Func:
 		// 生成完整的函数类型签名
 		// 例如: func(a int, b string) error 或 func(a int, rest ...string) (int, error)
@@ -256,7 +269,7 @@ func markTypeImportsUsed(t reflect.Type, fileCache *FileCache, srcPkgPath string
 }
 
 // writeParameterConversion 写入参数类型转换代码，返回下一个 ctx 索引
-func writeParameterConversion(b *strings.Builder, paramTypes []reflect.Type, paramNames []string, endIdx int, fileCache *FileCache, origPkgName, importAlias string) int {
+func writeParameterConversion(b *strings.Builder, paramTypes []reflect.Type, paramNames []string, endIdx int, fileCache *FileCache, origPkgName, importAlias, srcPkgPath string, config *Config) int {
 	ctxIndex := 0
 	for i := 0; i < endIdx; i++ {
 		// 特殊处理：context.Context 不从 ctx 读取，直接在调用处使用 ctx.GoContext()
@@ -272,6 +285,14 @@ func writeParameterConversion(b *strings.Builder, paramTypes []reflect.Type, par
 		// 根据真实类型自动标记导入（避免硬编码包名）
 		markTypeImportsUsed(paramTypes[i], fileCache, "")
 
+		// 函数类型参数：生成适配闭包，把脚本回调桥接为 Go 函数
+		if isCallbackParam(paramTypes[i], fileCache) {
+			writeCallbackConversion(b, paramTypes[i], pName, ctxIndex, fileCache, origPkgName, importAlias, srcPkgPath, config)
+			fmt.Fprintf(b, "\tif err != nil { return nil, data.NewErrorThrow(nil, fmt.Errorf(\"参数转换失败: %%v\", err)) }\n")
+			ctxIndex++
+			continue
+		}
+
 		// 检查是否为类型别名，需要两步转换
 		if isTypeAlias(paramTypes[i]) {
 			underlyingTypeStr := getUnderlyingTypeString(paramTypes[i], fileCache)
@@ -314,3 +335,91 @@ func writeVariadicParameterHandling(b *strings.Builder, isVariadic bool, variadi
 		b.WriteString("\n")
 	}
 }
+
+// splitErrorReturn 拆分返回值：末尾为 error 时单独处理，返回其余返回值
+func splitErrorReturn(returnTypes []reflect.Type) ([]reflect.Type, bool) {
+	if n := len(returnTypes); n > 0 && isBuiltinErrorType(returnTypes[n-1]) {
+		return returnTypes[:n-1], true
+	}
+	return returnTypes, false
+}
+
+// writeCallArgs 写入调用实参（context.Context 改为 ctx.GoContext()，可变参数展开）
+func writeCallArgs(b *strings.Builder, paramTypes []reflect.Type, paramNames []string, isVariadic bool) {
+	for i, pName := range paramNames {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		switch {
+		case isVariadic && i == len(paramNames)-1:
+			fmt.Fprintf(b, "%s...", pName)
+		case i < len(paramTypes) && isContextType(paramTypes[i]):
+			b.WriteString("ctx.GoContext()")
+		default:
+			b.WriteString(pName)
+		}
+	}
+}
+
+// writeCallAndReturn 写入目标调用与返回值处理
+// 末尾的 error 非 nil 时返回 data.NewErrorThrow，否则只返回其余返回值
+// 两个及以上返回值时封装为结果类 result
+func writeCallAndReturn(b *strings.Builder, callee string, paramTypes []reflect.Type, paramNames []string, isVariadic bool, returnTypes []reflect.Type, result *resultClass, srcPkgPath string, config *Config, fileCache *FileCache) {
+	values, hasErr := splitErrorReturn(returnTypes)
+
+	b.WriteString("\t")
+	switch {
+	case len(values) == 0 && hasErr:
+		// 仅返回 error：使用 if 语句内声明，避免与参数转换中的 err 重复声明
+		b.WriteString("if err := ")
+	case len(values) > 0:
+		lhs := make([]string, 0, len(returnTypes))
+		for i := range values {
+			lhs = append(lhs, fmt.Sprintf("ret%d", i))
+		}
+		if hasErr {
+			lhs = append(lhs, "err")
+		}
+		b.WriteString(strings.Join(lhs, ", ") + " := ")
+	}
+	fmt.Fprintf(b, "%s(", callee)
+	writeCallArgs(b, paramTypes, paramNames, isVariadic)
+	b.WriteString(")")
+
+	// 回调中的脚本异常优先于 Go 返回的 error 抛回脚本
+	if hasErr {
+		if len(values) == 0 {
+			b.WriteString("; err != nil {\n")
+		} else {
+			b.WriteString("\n")
+			writeCallbackChecks(b, "\t", paramTypes, paramNames, isVariadic, fileCache)
+			b.WriteString("\tif err != nil {\n")
+		}
+		writeCallbackChecks(b, "\t\t", paramTypes, paramNames, isVariadic, fileCache)
+		b.WriteString("\t\treturn nil, data.NewErrorThrow(nil, err)\n\t}\n")
+	} else {
+		b.WriteString("\n")
+	}
+	if len(values) == 0 || !hasErr {
+		writeCallbackChecks(b, "\t", paramTypes, paramNames, isVariadic, fileCache)
+	}
+
+	writeReturnValues(b, values, result, srcPkgPath, config, fileCache)
+}
+
+// writeReturnValues 写入返回值封装（不含 error）
+func writeReturnValues(b *strings.Builder, values []reflect.Type, result *resultClass, srcPkgPath string, config *Config, fileCache *FileCache) {
+	switch len(values) {
+	case 0:
+		b.WriteString("\treturn nil, nil\n")
+	case 1:
+		fmt.Fprintf(b, "\treturn %s, nil\n", returnValueExpr(values[0], "ret0", srcPkgPath, config, fileCache))
+	default:
+		// 多返回值：按位置传入结果类
+		exprs := make([]string, len(values))
+		for i, t := range values {
+			exprs[i] = returnValueExpr(t, fmt.Sprintf("ret%d", i), srcPkgPath, config, fileCache)
+		}
+		fmt.Fprintf(b, "\treturn data.NewClassValue(New%sClassFrom(%s), ctx), nil\n", result.Name, strings.Join(exprs, ", "))
+	}
+}
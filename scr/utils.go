@@ -3,6 +3,7 @@ package scr
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/php-any/origami/data"
@@ -26,6 +27,29 @@ func isBuiltinErrorType(t reflect.Type) bool {
 	return t != nil && t.Kind() == reflect.Interface && t.PkgPath() == "" && t.Name() == "error"
 }
 
+// isGeneratedClassType 判断类型（非指针）是否对应生成的类
+// 导出的具名结构体与非空接口会生成类，黑名单中的类型除外
+func isGeneratedClassType(t reflect.Type, config *Config) bool {
+	if t.PkgPath() == "" || !IsExportedType(t.Name()) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return false
+		}
+	default:
+		return false
+	}
+	return config == nil || !isBlacklistedType(t, config)
+}
+
+// scriptClassName 返回生成类在脚本中的完整类名，与类文件中 GetName 一致
+func scriptClassName(t reflect.Type, config *Config) string {
+	return scriptNamespace(t.PkgPath(), config) + "\\" + typeIdentName(t)
+}
+
 // checkMethodRecursiveGeneration 检查方法的参数和返回值是否需要递归生成
 // 用于 buildClass 中的方法检查
 func checkMethodRecursiveGeneration(m reflect.Method, cache *GroupCache) {
@@ -36,7 +60,11 @@ func checkMethodRecursiveGeneration(m reflect.Method, cache *GroupCache) {
 			continue
 		}
 		if isTypeNeedsProxy(outType) {
-			_ = generateFromType(outType, cache, nil)
+			generateDependency(outType, cache)
+		}
+		// 切片、map 等容器的元素
+		if outType.Kind() != reflect.Func {
+			checkWrappedRecursiveGeneration(outType, cache)
 		}
 	}
 
@@ -44,7 +72,11 @@ func checkMethodRecursiveGeneration(m reflect.Method, cache *GroupCache) {
 	for ii := 1; ii < m.Type.NumIn(); ii++ {
 		paramType := m.Type.In(ii)
 		if isPtrToStruct(paramType) {
-			_ = generateFromType(paramType, cache, nil)
+			generateDependency(paramType, cache)
+		}
+		// 回调参数：Go 调用脚本回调时封装实参
+		if paramType.Kind() == reflect.Func {
+			checkWrappedRecursiveGeneration(paramType, cache)
 		}
 	}
 }
@@ -59,7 +91,11 @@ func checkFunctionRecursiveGeneration(t reflect.Type, cache *GroupCache) {
 			continue
 		}
 		if isTypeNeedsProxy(outType) {
-			_ = generateFromType(outType, cache, nil)
+			generateDependency(outType, cache)
+		}
+		// 切片、map 等容器的元素
+		if outType.Kind() != reflect.Func {
+			checkWrappedRecursiveGeneration(outType, cache)
 		}
 	}
 
@@ -67,11 +103,90 @@ func checkFunctionRecursiveGeneration(t reflect.Type, cache *GroupCache) {
 	for i := 0; i < t.NumIn(); i++ {
 		paramType := t.In(i)
 		if isPtrToStruct(paramType) {
-			_ = generateFromType(paramType, cache, nil)
+			generateDependency(paramType, cache)
+		}
+		// 回调参数：Go 调用脚本回调时封装实参
+		if paramType.Kind() == reflect.Func {
+			checkWrappedRecursiveGeneration(paramType, cache)
 		}
 	}
 }
 
+// checkWrappedRecursiveGeneration 提交封装为类值时引用的生成类的生成任务，保证封装表达式引用的构造函数存在
+// 容器类型（切片、数组、map、channel）逐层检查元素类型；函数类型检查其参数（脚本回调的实参会被封装）
+func checkWrappedRecursiveGeneration(t reflect.Type, cache *GroupCache) {
+	switch {
+	case isPtrToStruct(t):
+		generateDependency(t, cache)
+	case t.Kind() == reflect.Interface && t.PkgPath() != "" && t.Name() != "":
+		generateDependency(t, cache)
+	case t.Kind() == reflect.Struct && t.PkgPath() != "" && t.Name() != "":
+		generateDependency(reflect.PointerTo(t), cache)
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Array, t.Kind() == reflect.Map, t.Kind() == reflect.Chan:
+		checkWrappedRecursiveGeneration(t.Elem(), cache)
+	case t.Kind() == reflect.Func:
+		for i := 0; i < t.NumIn(); i++ {
+			checkWrappedRecursiveGeneration(t.In(i), cache)
+		}
+	}
+}
+
+// checkImportable 检查类型表达式中出现的具名类型能否在生成代码中引用，不能时返回 error
+// 函数类型检查全部参数与返回值，容器类型逐层检查元素；不深入具名类型的定义
+func checkImportable(t reflect.Type) error {
+	if bad, reason := unimportableType(t); bad != nil {
+		return fmt.Errorf("引用了无法导入的类型 %s（%s）", bad, reason)
+	}
+	return nil
+}
+
+// unimportableType 返回类型表达式中第一个无法引用的具名类型及原因，全部可引用时返回 nil
+func unimportableType(t reflect.Type) (reflect.Type, string) {
+	if t.Name() != "" && t.PkgPath() != "" {
+		if isInternalPath(t.PkgPath()) {
+			return t, "位于 internal 包 " + t.PkgPath()
+		}
+		if !IsExportedType(t.Name()) {
+			return t, "未导出"
+		}
+		return nil, ""
+	}
+
+	var elems []reflect.Type
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		elems = append(elems, t.Elem())
+	case reflect.Map:
+		elems = append(elems, t.Key(), t.Elem())
+	case reflect.Func:
+		for i := 0; i < t.NumIn(); i++ {
+			elems = append(elems, t.In(i))
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			elems = append(elems, t.Out(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			elems = append(elems, t.Field(i).Type)
+		}
+	case reflect.Interface:
+		for i := 0; i < t.NumMethod(); i++ {
+			elems = append(elems, t.Method(i).Type)
+		}
+	}
+	for _, elem := range elems {
+		if bad, reason := unimportableType(elem); bad != nil {
+			return bad, reason
+		}
+	}
+	return nil, ""
+}
+
+// isInternalPath 判断包路径是否位于 internal 目录下（生成代码所在的模块无法导入）
+func isInternalPath(pkgPath string) bool {
+	return slices.Contains(strings.Split(pkgPath, "/"), "internal")
+}
+
 // ConvertFromIndex 泛型函数，从 Context 中安全获取指定索引的参数并转换为目标类型
 func ConvertFromIndex[T any](ctx data.Context, index int) (T, error) {
 	var zero T
@@ -0,0 +1,113 @@
+package scr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hidden 测试用未导出类型
+type hidden struct{}
+
+func TestUnimportableType(t *testing.T) {
+	tests := []struct {
+		name string
+		t    reflect.Type
+		bad  reflect.Type
+	}{
+		{"基础类型", reflect.TypeFor[int](), nil},
+		{"导出类型", reflect.TypeFor[*time.Time](), nil},
+		{"导出函数签名", reflect.TypeFor[func(time.Duration, []string) (map[string]*time.Time, error)](), nil},
+		{"未导出类型", reflect.TypeFor[hidden](), reflect.TypeFor[hidden]()},
+		{"参数中的未导出类型", reflect.TypeFor[func(int, *hidden)](), reflect.TypeFor[hidden]()},
+		{"返回值中的未导出类型", reflect.TypeFor[func() (map[string][]hidden, error)](), reflect.TypeFor[hidden]()},
+		{"回调参数中的未导出类型", reflect.TypeFor[func(func(chan hidden))](), reflect.TypeFor[hidden]()},
+		{"匿名结构体字段", reflect.TypeFor[struct{ H hidden }](), reflect.TypeFor[hidden]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bad, _ := unimportableType(tt.t); bad != tt.bad {
+				t.Errorf("unimportableType(%s) = %v, 期望 %v", tt.t, bad, tt.bad)
+			}
+		})
+	}
+}
+
+func TestIsInternalPath(t *testing.T) {
+	tests := []struct {
+		pkgPath string
+		want    bool
+	}{
+		{"github.com/redis/go-redis/v9", false},
+		{"github.com/redis/go-redis/v9/internal", true},
+		{"github.com/redis/go-redis/v9/internal/pool", true},
+		{"internal/poll", true},
+		{"github.com/example/internals", false},
+	}
+
+	for _, tt := range tests {
+		if got := isInternalPath(tt.pkgPath); got != tt.want {
+			t.Errorf("isInternalPath(%q) = %v, 期望 %v", tt.pkgPath, got, tt.want)
+		}
+	}
+}
+
+// UsesHidden 签名引用未导出类型的导出函数
+func UsesHidden(h hidden) {}
+
+func TestGenerateSkipsUnimportableSignature(t *testing.T) {
+	out := NewMemoryFS()
+	report, err := GenerateFromAny(UsesHidden, &Config{OutputRoot: "out", NamePrefix: "test", Output: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Diagnostics) != 1 || !strings.Contains(report.Diagnostics[0].Reason, "无法导入的类型 scr.hidden") {
+		t.Fatalf("Diagnostics = %v, 期望一条无法导入的诊断", report.Diagnostics)
+	}
+	for _, p := range out.Paths() {
+		if strings.HasSuffix(p, "useshidden_func.go") {
+			t.Errorf("不应生成 %s", p)
+		}
+	}
+}
+
+func TestEmitFileRejectsInvalidSource(t *testing.T) {
+	out := NewMemoryFS()
+	cache := NewGroupCache(&Config{OutputRoot: "out", NamePrefix: "test", Output: out})
+	if err := emitFile("out/a/a.go", "a", "func (", cache); err == nil {
+		t.Fatal("期望格式化失败返回 error")
+	}
+	if paths := out.Paths(); len(paths) != 0 {
+		t.Errorf("格式化失败时不应写出文件: %q", paths)
+	}
+}
+
+// UsesTime 参数引用其他包结构体的导出函数
+func UsesTime(t *time.Time) {}
+
+func TestGenerateSkipsBlacklistedDependency(t *testing.T) {
+	out := NewMemoryFS()
+	config := &Config{OutputRoot: "out", NamePrefix: "test", Output: out}
+	config.Blacklist.Packages = []string{"time"}
+	if _, err := GenerateFromAny(UsesTime, config); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range out.Paths() {
+		if strings.HasPrefix(p, "out/time/") {
+			t.Errorf("黑名单包不应生成 %s", p)
+		}
+	}
+}
+
+func TestBuildLoadFileBodyStrict(t *testing.T) {
+	const call = "utils.SetStrictNumericStrings(true)"
+	if body := buildLoadFileBody("a", []string{"User"}, nil, false); strings.Contains(body, call) {
+		t.Errorf("未开启严格模式时不应调用 %s:\n%s", call, body)
+	}
+	body := buildLoadFileBody("a", []string{"User"}, nil, true)
+	if !strings.Contains(body, call) || !strings.Contains(body, `"github.com/php-any/generator/utils"`) {
+		t.Errorf("开启严格模式时 Load 应调用 %s:\n%s", call, body)
+	}
+}
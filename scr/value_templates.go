@@ -0,0 +1,163 @@
+package scr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueTemplates 返回值封装模块
+//
+// 按 Go 类型生成把返回值封装为脚本值的表达式：基础类型使用对应的 data.*Value，
+// 切片/数组与 map 逐个元素封装，channel 封装为 utils.Channel（接收时逐个元素封装），
+// 生成类的结构体（指针或值）与接口封装为类值：同一输出子包直接调用 NewXxxClassFrom，
+// 其他输出子包（如 time.Time 字段）导入该子包后调用；any 按动态类型由 utils.ValueOf 封装，
+// 其余回退 data.NewAnyValue。
+
+// returnValueExpr 单个返回值或字段封装为 data.Value 的表达式（表达式中可使用 ctx）
+func returnValueExpr(t reflect.Type, name, srcPkgPath string, config *Config, fileCache *FileCache) string {
+	return wrapValueExpr(t, name, 0, srcPkgPath, config, fileCache)
+}
+
+// wrapValueExpr 递归生成封装表达式，depth 用于区分嵌套闭包的参数名
+func wrapValueExpr(t reflect.Type, name string, depth int, srcPkgPath string, config *Config, fileCache *FileCache) string {
+	switch {
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		// nil 指针封装为 null
+		if from, ok := classFromFunc(t.Elem(), srcPkgPath, config, fileCache); ok {
+			fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+			return fmt.Sprintf("utils.NewClassValueOf(%s, %s, ctx)", name, from)
+		}
+	case t.Kind() == reflect.Struct:
+		// 值结构体：name 为局部变量（retN、闭包参数）或源对象字段，均可取址
+		if from, ok := classFromFunc(t, srcPkgPath, config, fileCache); ok {
+			return fmt.Sprintf("data.NewClassValue(%s(&%s), ctx)", from, name)
+		}
+	case t.Kind() == reflect.Interface:
+		if from, ok := classFromFunc(t, srcPkgPath, config, fileCache); ok {
+			fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+			return fmt.Sprintf("utils.NewClassValueOf(%s, %s, ctx)", name, from)
+		}
+		// any：map[string]any 等动态值按实际类型封装，脚本可直接读取
+		if t.PkgPath() == "" && t.NumMethod() == 0 {
+			fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+			return fmt.Sprintf("utils.ValueOf(%s)", name)
+		}
+	case t.Kind() == reflect.Uint || t.Kind() == reflect.Uint64:
+		// 超出 int 范围时转为浮点，避免回绕为负数
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		return fmt.Sprintf("utils.NewUintValue(%s)", name)
+	case isIntKind(t.Kind()):
+		return fmt.Sprintf("data.NewIntValue(%s)", convertExpr(t, reflect.Int, name))
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return fmt.Sprintf("data.NewFloatValue(%s)", convertExpr(t, reflect.Float64, name))
+	case t.Kind() == reflect.String:
+		return fmt.Sprintf("data.NewStringValue(%s)", convertExpr(t, reflect.String, name))
+	case t.Kind() == reflect.Bool:
+		return fmt.Sprintf("data.NewBoolValue(%s)", convertExpr(t, reflect.Bool, name))
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if wrap, ok := elemWrapFunc(t.Elem(), depth, srcPkgPath, config, fileCache); ok {
+			if t.Kind() == reflect.Array {
+				name += "[:]"
+			}
+			return fmt.Sprintf("utils.NewArrayValueFrom(%s, %s)", name, wrap)
+		}
+	case t.Kind() == reflect.Map:
+		helper := ""
+		switch {
+		case t.Key().Kind() == reflect.String:
+			helper = "utils.NewObjectValueFrom"
+		case t.Key().Comparable():
+			helper = "utils.NewObjectValueFromMap"
+		}
+		if helper != "" {
+			if wrap, ok := elemWrapFunc(t.Elem(), depth, srcPkgPath, config, fileCache); ok {
+				return fmt.Sprintf("%s(%s, %s)", helper, name, wrap)
+			}
+		}
+	case t.Kind() == reflect.Chan:
+		// 元素类型无法书写时 wrap 传 nil，元素回退为 AnyValue
+		fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+		wrap, ok := elemWrapFunc(t.Elem(), depth, srcPkgPath, config, fileCache)
+		if !ok {
+			wrap = "nil"
+		}
+		return fmt.Sprintf("%s(%s, %s, ctx)", channelValueFunc(t.ChanDir()), name, wrap)
+	}
+	return fmt.Sprintf("data.NewAnyValue(%s)", name)
+}
+
+// classFromFunc 返回生成类 t 的 NewXxxClassFrom 构造函数引用
+// 同一输出子包直接引用；其他输出子包以 子包名+gen 为别名导入后引用（如 timegen.NewTimeClassFrom），
+// 不是生成类或无法确定输出子包的导入路径时返回 false
+func classFromFunc(t reflect.Type, srcPkgPath string, config *Config, fileCache *FileCache) (string, bool) {
+	if !isGeneratedClassType(t, config) {
+		return "", false
+	}
+	from := "New" + typeIdentName(t) + "ClassFrom"
+	pkgName := outputPackageName(t.PkgPath(), config)
+	if t.PkgPath() == srcPkgPath || pkgName == outputPackageName(srcPkgPath, config) {
+		return from, true
+	}
+	importPath := outputImportPath(pkgName, config)
+	if importPath == "" {
+		return "", false
+	}
+	if _, ok := fileCache.GetImports()[importPath]; !ok {
+		fileCache.AddImport(importPath, pkgName+"gen")
+	}
+	fileCache.MarkImportUsed(importPath)
+	return fileCache.GetImports()[importPath] + "." + from, true
+}
+
+// channelValueFunc 按 channel 方向选择 utils 中的封装函数
+func channelValueFunc(dir reflect.ChanDir) string {
+	switch dir {
+	case reflect.RecvDir:
+		return "utils.NewRecvChannelValue"
+	case reflect.SendDir:
+		return "utils.NewSendChannelValue"
+	}
+	return "utils.NewChannelValue"
+}
+
+// elemWrapFunc 生成元素封装闭包 func(itemN T) data.Value { ... }
+// 元素类型无法在生成文件中书写时返回 false，由调用方整体回退 AnyValue
+func elemWrapFunc(elem reflect.Type, depth int, srcPkgPath string, config *Config, fileCache *FileCache) (string, bool) {
+	if !isWritableType(elem, fileCache) {
+		return "", false
+	}
+	fileCache.MarkImportUsed("github.com/php-any/generator/utils")
+	markTypeImportsUsed(elem, fileCache, srcPkgPath)
+
+	item := fmt.Sprintf("item%d", depth)
+	body := wrapValueExpr(elem, item, depth+1, srcPkgPath, config, fileCache)
+	return fmt.Sprintf("func(%s %s) data.Value { return %s }", item, getTypeString(elem, fileCache), body), true
+}
+
+// convertExpr 必要时把值转换为 data 构造函数接受的基础类型（具名类型或不同宽度）
+func convertExpr(t reflect.Type, target reflect.Kind, name string) string {
+	if t.PkgPath() == "" && t.Kind() == target {
+		return name
+	}
+	return fmt.Sprintf("%s(%s)", target.String(), name)
+}
+
+// isWritableType 判断类型能否在生成文件中书写：
+// 内置类型，或所属包已在文件导入中（源包总是已导入）
+func isWritableType(t reflect.Type, fileCache *FileCache) bool {
+	if t.PkgPath() != "" {
+		_, ok := fileCache.GetImports()[t.PkgPath()]
+		return ok && IsExportedType(t.Name())
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		return isWritableType(t.Elem(), fileCache)
+	case reflect.Map:
+		return isWritableType(t.Key(), fileCache) && isWritableType(t.Elem(), fileCache)
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Struct, reflect.Func, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/php-any/origami/data"
+)
+
+// Callback 脚本回调（闭包或函数值）在 Go 侧的调用桥
+//
+// 生成代码为 Go 函数类型参数构造适配闭包，闭包内通过 Call 执行脚本回调：
+// 参数由生成代码封装为脚本值，返回值再用 Convert 转回 Go 类型。
+// 回调中抛出的脚本异常以 error 返回给 Go 调用方，同时记录下来，
+// 待 Go 函数返回后由生成代码通过 Control 重新抛回脚本。
+type Callback struct {
+	ctx data.Context
+	fn  data.FuncStmt
+	// callable 预留给实现了 data.CallableValue 的值
+	callable data.CallableValue
+
+	mu  sync.Mutex
+	ctl data.Control
+}
+
+// FuncFromIndex 从上下文索引位置读取 Go 函数类型参数
+//
+// - 参数缺省或为 null：返回 F 的零值（nil 函数）
+// - 参数本身封装了 F 类型的 Go 函数：直接返回
+// - 参数为脚本回调：用 adapt 构造适配函数，并返回对应的 Callback
+func FuncFromIndex[F any](ctx data.Context, index int, adapt func(cb *Callback) F) (F, *Callback, error) {
+	var result F
+	v, ok := ctx.GetIndexValue(index)
+	if !ok || v == nil {
+		return result, nil, nil
+	}
+	if _, ok := v.(*data.NullValue); ok {
+		return result, nil, nil
+	}
+	if converted, err := convertValue[F](v); err == nil {
+		return converted, nil, nil
+	}
+
+	cb, ok := newCallback(ctx, v)
+	if !ok {
+		return result, nil, fmt.Errorf("参数索引 %d 不是可调用值: %T", index, v)
+	}
+	return adapt(cb), cb, nil
+}
+
+// newCallback 由脚本函数值或可调用值创建 Callback
+func newCallback(ctx data.Context, v data.Value) (*Callback, bool) {
+	switch fv := v.(type) {
+	case *data.FuncValue:
+		return &Callback{ctx: ctx, fn: fv.Value}, true
+	case data.CallableValue:
+		return &Callback{ctx: ctx, callable: fv}, true
+	}
+	return nil, false
+}
+
+// Call 以 args 作为位置参数调用脚本回调
+// 脚本抛出异常时记录该异常并返回对应的 error
+func (c *Callback) Call(args ...data.Value) (data.Value, error) {
+	ret, ctl := c.call(args)
+	if ctl != nil {
+		c.record(ctl)
+		return nil, ControlError(ctl)
+	}
+	if ret == nil {
+		return data.NewNullValue(), nil
+	}
+	return ret, nil
+}
+
+// Fail 记录 Go 侧处理回调结果时的错误（如返回值无法转换为 Go 类型），
+// 用于没有 error 返回值、无法把错误交给 Go 调用方的回调；Go 函数返回后与脚本异常一样抛回脚本
+func (c *Callback) Fail(err error) {
+	c.record(data.NewErrorThrow(nil, err))
+}
+
+// record 记录第一个异常
+func (c *Callback) record(ctl data.Control) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctl == nil {
+		c.ctl = ctl
+	}
+}
+
+// call 按回调的参数列表把 args 写入新的函数上下文后执行
+func (c *Callback) call(args []data.Value) (data.Value, data.Control) {
+	if c.callable != nil {
+		return c.callable.Call(args...)
+	}
+
+	fnCtx := c.ctx.CreateContext(c.fn.GetVariables())
+	for i, param := range c.fn.GetParams() {
+		if i < len(args) {
+			if variable, ok := param.(data.Variable); ok {
+				if ctl := variable.SetValue(fnCtx, args[i]); ctl != nil {
+					return nil, ctl
+				}
+			}
+			continue
+		}
+		// 未传入的参数按默认值初始化
+		if _, ctl := param.GetValue(fnCtx); ctl != nil {
+			return nil, ctl
+		}
+	}
+
+	v, ctl := c.fn.Call(fnCtx)
+	if ctl != nil {
+		return nil, ctl
+	}
+	ret, _ := v.(data.Value)
+	return ret, nil
+}
+
+// Control 返回回调执行期间记录的第一个脚本异常，未发生异常或 c 为 nil 时返回 nil
+func (c *Callback) Control() data.Control {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ctl
+}
+
+// ControlError 将脚本异常转换为 Go error
+func ControlError(ctl data.Control) error {
+	switch v := ctl.(type) {
+	case data.ThrowControl:
+		if err := v.GetError(); err != nil {
+			return err
+		}
+	case *data.ClassValue:
+		// throw new Exception("...")：取 message 属性
+		if prop, ok := v.GetProperty("message"); ok {
+			if msg, ctl := prop.GetValue(v); ctl == nil {
+				if s, ok := msg.(data.Value); ok {
+					return errors.New(s.AsString())
+				}
+			}
+		}
+	}
+	return errors.New(ctl.AsString())
+}
+
+// IndexValue 返回脚本数组第 i 个元素，用于把回调的数组返回值拆成多个 Go 返回值
+// 不是数组或越界时返回 null
+func IndexValue(v data.Value, i int) data.Value {
+	if arr, ok := v.(*data.ArrayValue); ok && i < len(arr.Value) {
+		return arr.Value[i]
+	}
+	return data.NewNullValue()
+}
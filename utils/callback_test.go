@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/php-any/origami/data"
+)
+
+// fakeCallable 测试用可调用值，按 fn 返回结果并记录调用实参
+type fakeCallable struct {
+	fn   func(args ...data.Value) (data.Value, data.Control)
+	args [][]data.Value
+}
+
+func (f *fakeCallable) GetValue(ctx data.Context) (data.GetValue, data.Control) { return f, nil }
+func (f *fakeCallable) AsString() string                                        { return "fake" }
+func (f *fakeCallable) IsMethod() bool                                          { return false }
+func (f *fakeCallable) GetMethodName() string                                   { return "" }
+
+func (f *fakeCallable) Call(args ...data.Value) (data.Value, data.Control) {
+	f.args = append(f.args, args)
+	return f.fn(args...)
+}
+
+// adaptPredicate 与生成代码相同形状的适配闭包：func(int) bool 没有 error 返回值，转换失败记录到回调
+func adaptPredicate(cb *Callback) func(int) bool {
+	return func(arg0 int) (r0 bool) {
+		ret, err := cb.Call(ValueOf(arg0))
+		if err != nil {
+			return
+		}
+		if r0, err = Convert[bool](ret); err != nil {
+			cb.Fail(err)
+			return
+		}
+		return
+	}
+}
+
+func TestCallbackBridge(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(args ...data.Value) (data.Value, data.Control)
+		want bool
+		// 期望 Control 记录的错误内容，为空时期望没有异常
+		err string
+	}{
+		{
+			name: "返回值转换为 Go 类型",
+			fn: func(args ...data.Value) (data.Value, data.Control) {
+				return data.NewBoolValue(args[0].AsString() == "2"), nil
+			},
+			want: true,
+		},
+		{
+			name: "脚本异常记录到回调",
+			fn: func(args ...data.Value) (data.Value, data.Control) {
+				return nil, data.NewErrorThrow(nil, errors.New("回调失败"))
+			},
+			err: "回调失败",
+		},
+		{
+			name: "返回值无法转换时记录到回调",
+			fn: func(args ...data.Value) (data.Value, data.Control) {
+				return data.NewArrayValue(nil), nil
+			},
+			err: "bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callable := &fakeCallable{fn: tt.fn}
+			cb := &Callback{callable: callable}
+			if got := adaptPredicate(cb)(2); got != tt.want {
+				t.Errorf("回调结果 = %v, 期望 %v", got, tt.want)
+			}
+			if len(callable.args) != 1 || callable.args[0][0].AsString() != "2" {
+				t.Errorf("回调实参 = %v", callable.args)
+			}
+
+			ctl := cb.Control()
+			if tt.err == "" {
+				if ctl != nil {
+					t.Errorf("Control() = %v, 期望 nil", ctl.AsString())
+				}
+				return
+			}
+			if ctl == nil {
+				t.Fatalf("Control() = nil, 期望记录包含 %q 的异常", tt.err)
+			}
+			if err := ControlError(ctl); !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ControlError() = %v, 期望包含 %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCallbackRecordsFirstControl(t *testing.T) {
+	cb := &Callback{callable: &fakeCallable{fn: func(args ...data.Value) (data.Value, data.Control) {
+		return nil, data.NewErrorThrow(nil, errors.New("第一次"))
+	}}}
+	if _, err := cb.Call(); err == nil || !strings.Contains(err.Error(), "第一次") {
+		t.Fatalf("Call() error = %v", err)
+	}
+	cb.Fail(errors.New("第二次"))
+	if err := ControlError(cb.Control()); !strings.Contains(err.Error(), "第一次") {
+		t.Errorf("Control() = %v, 期望保留第一次的异常", err)
+	}
+
+	var nilCallback *Callback
+	if ctl := nilCallback.Control(); ctl != nil {
+		t.Errorf("nil Callback 的 Control() = %v", ctl)
+	}
+}
+
+func TestIndexValue(t *testing.T) {
+	arr := data.NewArrayValue([]data.Value{data.NewIntValue(1), data.NewStringValue("a")})
+	tests := []struct {
+		v    data.Value
+		i    int
+		want string
+	}{
+		{arr, 0, "1"},
+		{arr, 1, "a"},
+		{arr, 2, "null"},
+		{data.NewIntValue(1), 0, "null"},
+	}
+	for _, tt := range tests {
+		if got := IndexValue(tt.v, tt.i); got.AsString() != tt.want {
+			t.Errorf("IndexValue(%s, %d) = %s, 期望 %s", tt.v.AsString(), tt.i, got.AsString(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// ChannelClassName 封装类在脚本中的类名，生成代码用它声明 channel 参数与返回值的类型
+const ChannelClassName = "Channel"
+
+// Channel Go channel 在脚本中的封装类
+//
+// 生成代码按 channel 方向选用 NewChannelValue / NewRecvChannelValue / NewSendChannelValue，
+// wrap 把接收到的元素封装为生成类或原生脚本值，发送时用 Convert 转回元素类型。
+// 脚本方法：send($value)、receive($timeout)、close()、len()、cap()，
+// 以及用于遍历的 each($fn) 与 toArray()：脚本的 foreach 只支持数组与对象，
+// 遍历 channel 写作 foreach ($ch->toArray() as $v) 或 $ch->each(($v) => {...})。
+// 只能接收的 channel 调用 send/close、只能发送的 channel 调用 receive/each/toArray 时抛出异常。
+type Channel[T any] struct {
+	// source 原始 channel，GetSource 返回它以便原样传回 Go 函数
+	source any
+	recv   <-chan T
+	send   chan<- T
+	wrap   func(T) data.Value
+}
+
+// NewChannelValue 封装双向 channel，nil channel 返回 null
+func NewChannelValue[T any](ch chan T, wrap func(T) data.Value, ctx data.Context) data.Value {
+	return newChannelValue(ch, ch, ch, wrap, ctx)
+}
+
+// NewRecvChannelValue 封装只能接收的 channel（<-chan T）
+func NewRecvChannelValue[T any](ch <-chan T, wrap func(T) data.Value, ctx data.Context) data.Value {
+	return newChannelValue(ch, ch, nil, wrap, ctx)
+}
+
+// NewSendChannelValue 封装只能发送的 channel（chan<- T）
+func NewSendChannelValue[T any](ch chan<- T, wrap func(T) data.Value, ctx data.Context) data.Value {
+	return newChannelValue(ch, nil, ch, wrap, ctx)
+}
+
+func newChannelValue[T any](source any, recv <-chan T, send chan<- T, wrap func(T) data.Value, ctx data.Context) data.Value {
+	if isNil(source) {
+		return data.NewNullValue()
+	}
+	if wrap == nil {
+		wrap = func(item T) data.Value { return data.NewAnyValue(item) }
+	}
+	return data.NewClassValue(&Channel[T]{source: source, recv: recv, send: send, wrap: wrap}, ctx)
+}
+
+func (c *Channel[T]) GetSource() any { return c.source }
+
+func (c *Channel[T]) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(c, ctx.CreateBaseContext()), nil
+}
+
+func (c *Channel[T]) GetFrom() data.From                            { return nil }
+func (c *Channel[T]) GetName() string                               { return ChannelClassName }
+func (c *Channel[T]) GetExtend() *string                            { return nil }
+func (c *Channel[T]) GetImplements() []string                       { return nil }
+func (c *Channel[T]) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *Channel[T]) GetProperties() map[string]data.Property       { return map[string]data.Property{} }
+func (c *Channel[T]) GetConstruct() data.Method                     { return nil }
+
+// channelMethodNames 脚本方法名，GetMethods 按此顺序返回
+var channelMethodNames = []string{"send", "receive", "close", "len", "cap", "each", "toArray"}
+
+func (c *Channel[T]) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "send":
+		return &channelMethod{
+			name:   name,
+			params: []data.GetValue{node.NewParameter(nil, "value", 0, nil, nil)},
+			vars:   []data.Variable{node.NewVariable(nil, "value", 0, nil)},
+			ret:    data.Bool{},
+			call:   c.callSend,
+		}, true
+	case "receive":
+		return &channelMethod{
+			name:   name,
+			params: []data.GetValue{node.NewParameter(nil, "timeout", 0, data.NewIntValue(0), data.Int{})},
+			vars:   []data.Variable{node.NewVariable(nil, "timeout", 0, data.Int{})},
+			call:   c.callReceive,
+		}, true
+	case "close":
+		return &channelMethod{name: name, ret: data.NewBaseType("void"), call: c.callClose}, true
+	case "len":
+		return &channelMethod{name: name, ret: data.Int{}, call: func(ctx data.Context) (data.GetValue, data.Control) {
+			return data.NewIntValue(c.len()), nil
+		}}, true
+	case "cap":
+		return &channelMethod{name: name, ret: data.Int{}, call: func(ctx data.Context) (data.GetValue, data.Control) {
+			return data.NewIntValue(c.cap()), nil
+		}}, true
+	case "each":
+		return &channelMethod{
+			name:   name,
+			params: []data.GetValue{node.NewParameter(nil, "fn", 0, nil, data.Callable{})},
+			vars:   []data.Variable{node.NewVariable(nil, "fn", 0, data.Callable{})},
+			ret:    data.NewBaseType("void"),
+			call:   c.callEach,
+		}, true
+	case "toArray":
+		return &channelMethod{name: name, ret: data.Arrays{}, call: c.callToArray}, true
+	}
+	return nil, false
+}
+
+func (c *Channel[T]) GetMethods() []data.Method {
+	methods := make([]data.Method, 0, len(channelMethodNames))
+	for _, name := range channelMethodNames {
+		m, _ := c.GetMethod(name)
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// callSend 发送元素，channel 已关闭时返回 false
+func (c *Channel[T]) callSend(ctx data.Context) (data.GetValue, data.Control) {
+	if c.send == nil {
+		return nil, data.NewErrorThrow(nil, errors.New("只能接收的 channel 不能发送"))
+	}
+	v, ok := ctx.GetIndexValue(0)
+	if !ok {
+		return nil, data.NewErrorThrow(nil, errors.New("缺少参数: value"))
+	}
+	item, err := Convert[T](v)
+	if err != nil {
+		return nil, data.NewErrorThrow(nil, fmt.Errorf("channel 元素转换失败: %v", err))
+	}
+	return data.NewBoolValue(c.trySend(item)), nil
+}
+
+// trySend 向已关闭的 channel 发送会 panic，这里转为 false
+func (c *Channel[T]) trySend(item T) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+	c.send <- item
+	return true
+}
+
+// callReceive 接收一个元素；timeout 为毫秒，<= 0 时一直阻塞
+// channel 已关闭或超时返回 null
+func (c *Channel[T]) callReceive(ctx data.Context) (data.GetValue, data.Control) {
+	if c.recv == nil {
+		return nil, data.NewErrorThrow(nil, errors.New("只能发送的 channel 不能接收"))
+	}
+	timeout := 0
+	if v, ok := ctx.GetIndexValue(0); ok {
+		if iv, ok := v.(*data.IntValue); ok {
+			timeout, _ = iv.AsInt()
+		}
+	}
+
+	if timeout <= 0 {
+		item, ok := <-c.recv
+		if !ok {
+			return data.NewNullValue(), nil
+		}
+		return c.wrap(item), nil
+	}
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case item, ok := <-c.recv:
+		if !ok {
+			return data.NewNullValue(), nil
+		}
+		return c.wrap(item), nil
+	case <-timer.C:
+		return data.NewNullValue(), nil
+	}
+}
+
+// callClose 关闭 channel，重复关闭抛出异常
+func (c *Channel[T]) callClose(ctx data.Context) (data.GetValue, data.Control) {
+	if c.send == nil {
+		return nil, data.NewErrorThrow(nil, errors.New("只能接收的 channel 不能关闭"))
+	}
+	if err := c.tryClose(); err != nil {
+		return nil, data.NewErrorThrow(nil, err)
+	}
+	return nil, nil
+}
+
+func (c *Channel[T]) tryClose() (err error) {
+	defer func() {
+		if recover() != nil {
+			err = errors.New("channel 已关闭")
+		}
+	}()
+	close(c.send)
+	return nil
+}
+
+// callEach 逐个接收元素并调用 $fn，直到 channel 关闭；$fn 返回 false 时提前结束
+func (c *Channel[T]) callEach(ctx data.Context) (data.GetValue, data.Control) {
+	if c.recv == nil {
+		return nil, data.NewErrorThrow(nil, errors.New("只能发送的 channel 不能遍历"))
+	}
+	v, _ := ctx.GetIndexValue(0)
+	cb, ok := newCallback(ctx, v)
+	if !ok {
+		return nil, data.NewErrorThrow(nil, fmt.Errorf("each 参数不是可调用值: %T", v))
+	}
+	for item := range c.recv {
+		ret, err := cb.Call(c.wrap(item))
+		if err != nil {
+			return nil, cb.Control()
+		}
+		if b, ok := ret.(*data.BoolValue); ok {
+			if cont, _ := b.AsBool(); !cont {
+				break
+			}
+		}
+	}
+	return nil, nil
+}
+
+// callToArray 接收全部元素直到 channel 关闭，返回脚本数组
+func (c *Channel[T]) callToArray(ctx data.Context) (data.GetValue, data.Control) {
+	if c.recv == nil {
+		return nil, data.NewErrorThrow(nil, errors.New("只能发送的 channel 不能遍历"))
+	}
+	values := make([]data.Value, 0, len(c.recv))
+	for item := range c.recv {
+		values = append(values, c.wrap(item))
+	}
+	return data.NewArrayValue(values), nil
+}
+
+func (c *Channel[T]) len() int {
+	if c.recv != nil {
+		return len(c.recv)
+	}
+	return len(c.send)
+}
+
+func (c *Channel[T]) cap() int {
+	if c.recv != nil {
+		return cap(c.recv)
+	}
+	return cap(c.send)
+}
+
+// channelMethod Channel 的脚本方法
+type channelMethod struct {
+	name   string
+	params []data.GetValue
+	vars   []data.Variable
+	ret    data.Types
+	call   func(ctx data.Context) (data.GetValue, data.Control)
+}
+
+func (m *channelMethod) Call(ctx data.Context) (data.GetValue, data.Control) { return m.call(ctx) }
+func (m *channelMethod) GetName() string                                     { return m.name }
+func (m *channelMethod) GetModifier() data.Modifier                          { return data.ModifierPublic }
+func (m *channelMethod) GetIsStatic() bool                                   { return false }
+func (m *channelMethod) GetParams() []data.GetValue                          { return m.params }
+func (m *channelMethod) GetVariables() []data.Variable                       { return m.vars }
+func (m *channelMethod) GetReturnType() data.Types                           { return m.ret }
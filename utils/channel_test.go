@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/php-any/origami/data"
+)
+
+// argsContext 测试用调用上下文，只提供按下标读取的实参
+type argsContext struct {
+	data.Context
+	args []data.Value
+}
+
+func (c *argsContext) GetIndexValue(index int) (data.Value, bool) {
+	if index >= len(c.args) {
+		return nil, false
+	}
+	return c.args[index], true
+}
+
+// callChannel 调用 channel 封装类的脚本方法
+func callChannel(t *testing.T, v data.Value, name string, args ...data.Value) (data.GetValue, data.Control) {
+	t.Helper()
+	cv, ok := v.(*data.ClassValue)
+	if !ok {
+		t.Fatalf("channel 应封装为类值, 实际 %T", v)
+	}
+	m, ok := cv.Class.GetMethod(name)
+	if !ok {
+		t.Fatalf("缺少方法 %s", name)
+	}
+	return m.Call(&argsContext{args: args})
+}
+
+func wrapInt(v int) data.Value { return data.NewIntValue(v) }
+
+func TestChannelSendReceiveClose(t *testing.T) {
+	ch := make(chan int, 2)
+	v := NewChannelValue(ch, wrapInt, nil)
+
+	if ret, ctl := callChannel(t, v, "send", data.NewStringValue("1")); ctl != nil || ret.(*data.BoolValue).AsString() != "true" {
+		t.Fatalf("send = %v, %v", ret, ctl)
+	}
+	ch <- 2
+	if ret, _ := callChannel(t, v, "len"); ret.(data.Value).AsString() != "2" {
+		t.Errorf("len = %v, 期望 2", ret)
+	}
+	if ret, _ := callChannel(t, v, "cap"); ret.(data.Value).AsString() != "2" {
+		t.Errorf("cap = %v, 期望 2", ret)
+	}
+	if ret, ctl := callChannel(t, v, "receive"); ctl != nil || ret.(data.Value).AsString() != "1" {
+		t.Errorf("receive = %v, %v, 期望 1", ret, ctl)
+	}
+
+	if _, ctl := callChannel(t, v, "close"); ctl != nil {
+		t.Fatalf("close = %v", ctl)
+	}
+	// 关闭后仍可读完缓冲的元素
+	if ret, _ := callChannel(t, v, "toArray"); ret.(data.Value).AsString() != data.NewArrayValue([]data.Value{data.NewIntValue(2)}).AsString() {
+		t.Errorf("toArray = %v", ret.(data.Value).AsString())
+	}
+	if ret, _ := callChannel(t, v, "receive"); ret.(data.Value).AsString() != "null" {
+		t.Errorf("关闭后 receive = %v, 期望 null", ret)
+	}
+	if ret, _ := callChannel(t, v, "send", data.NewIntValue(3)); ret.(data.Value).AsString() != "false" {
+		t.Errorf("关闭后 send = %v, 期望 false", ret)
+	}
+	if _, ctl := callChannel(t, v, "close"); ctl == nil {
+		t.Error("重复关闭期望抛出异常")
+	}
+}
+
+func TestChannelReceiveTimeout(t *testing.T) {
+	v := NewChannelValue(make(chan int), wrapInt, nil)
+	if ret, ctl := callChannel(t, v, "receive", data.NewIntValue(10)); ctl != nil || ret.(data.Value).AsString() != "null" {
+		t.Errorf("超时 receive = %v, %v, 期望 null", ret, ctl)
+	}
+}
+
+func TestChannelDirection(t *testing.T) {
+	ch := make(chan int, 1)
+	recv := NewRecvChannelValue((<-chan int)(ch), wrapInt, nil)
+	send := NewSendChannelValue((chan<- int)(ch), wrapInt, nil)
+
+	for _, tt := range []struct {
+		v    data.Value
+		name string
+		args []data.Value
+	}{
+		{recv, "send", []data.Value{data.NewIntValue(1)}},
+		{recv, "close", nil},
+		{send, "receive", nil},
+		{send, "toArray", nil},
+	} {
+		if _, ctl := callChannel(t, tt.v, tt.name, tt.args...); ctl == nil {
+			t.Errorf("%s 期望因方向不符抛出异常", tt.name)
+		}
+	}
+
+	if _, ctl := callChannel(t, send, "send", data.NewStringValue("x")); ctl == nil || !strings.Contains(ctl.AsString(), "转换失败") {
+		t.Errorf("无法转换的元素期望抛出转换异常, 实际 %v", ctl)
+	}
+}
+
+func TestChannelConvertAndNil(t *testing.T) {
+	if v := NewChannelValue[int](nil, wrapInt, nil); v.AsString() != "null" {
+		t.Errorf("nil channel 封装为 %v, 期望 null", v.AsString())
+	}
+
+	ch := make(chan int)
+	got, err := Convert[chan int](NewChannelValue(ch, wrapInt, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ch {
+		t.Error("Convert 应还原为同一个 channel")
+	}
+}
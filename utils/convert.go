@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/php-any/origami/data"
+)
+
+// 按反射类型转换模块
+//
+// 泛型的 convertValue[S] 只能处理编译期已知的目标类型；切片元素、map 值这类嵌套位置
+// 的类型只在运行时可知，由 convertTo 按 reflect.Type 递归转换：
+// 生成类取其 Go 源对象，嵌套数组逐层转换，具名类型（time.Duration、type Status string 等）
+// 按底层类型转换后再转换为具名类型。出错时逐层报告失败的数组下标或 map 键。
+
+// convertTo 按目标类型 t 转换脚本值
+// - null：零值
+// - 封装的 Go 源对象（生成类、AnyValue）：可赋值或同种类可转换时直接使用，指针与值自动取址/解引用
+// - any 等接口：转换为原生 Go 值（见 toAny）
+// - 切片/数组/map：递归转换元素
+// - 指针：转换指向的类型后取址
+// - 基础类型及其具名类型：按底层类型转换后再转换为 t
+func convertTo(v data.Value, t reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(t), nil
+	}
+	if _, ok := v.(*data.NullValue); ok {
+		return reflect.Zero(t), nil
+	}
+	if src, ok := valueSource(v); ok {
+		if rv, ok := sourceTo(src, t); ok {
+			return rv, nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		natural := toAny(v)
+		if natural == nil {
+			return reflect.Zero(t), nil
+		}
+		if rv := reflect.ValueOf(natural); rv.Type().AssignableTo(t) {
+			return rv, nil
+		}
+	case reflect.Map:
+		return convertToMap(v, t)
+	case reflect.Slice, reflect.Array:
+		if arr, ok := v.(*data.ArrayValue); ok {
+			return convertToSlice(arr, t)
+		}
+	case reflect.Ptr:
+		elem, err := convertTo(v, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	default:
+		if scalar, ok, err := convertScalar(v, t.Kind()); ok {
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(scalar).Convert(t), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("无法将 %T 转换为 %s", v, t)
+}
+
+// convertToSlice 把脚本数组逐个元素转换为切片或定长数组 t，错误中带失败元素的下标
+func convertToSlice(arr *data.ArrayValue, t reflect.Type) (reflect.Value, error) {
+	var out reflect.Value
+	if t.Kind() == reflect.Array {
+		if len(arr.Value) > t.Len() {
+			return reflect.Value{}, fmt.Errorf("数组长度 %d 超出 %s 的长度", len(arr.Value), t)
+		}
+		out = reflect.New(t).Elem()
+	} else {
+		out = reflect.MakeSlice(t, len(arr.Value), len(arr.Value))
+	}
+	for i, item := range arr.Value {
+		elem, err := convertTo(item, t.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
+		}
+		out.Index(i).Set(elem)
+	}
+	return out, nil
+}
+
+// sourceTo 把 Go 源对象转换为 t：可直接赋值、同种类可转换（具名类型），
+// 或源对象与 t 只差一层指针（*User 传给 User 元素时解引用，User 传给 *User 时取址）
+func sourceTo(src any, t reflect.Type) (reflect.Value, bool) {
+	rv := reflect.ValueOf(src)
+	if !rv.IsValid() {
+		return rv, false
+	}
+	switch {
+	case rv.Type().AssignableTo(t):
+		return rv, true
+	case rv.Kind() == t.Kind() && rv.Kind() != reflect.Ptr && rv.Type().ConvertibleTo(t):
+		return rv.Convert(t), true
+	case rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Type().AssignableTo(t):
+		return rv.Elem(), true
+	case t.Kind() == reflect.Ptr && rv.Type().AssignableTo(t.Elem()):
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(rv)
+		return ptr, true
+	}
+	return rv, false
+}
+
+// convertScalar 按基础类型 kind 转换，kind 不是基础类型时 ok 为 false
+func convertScalar(v data.Value, kind reflect.Kind) (result any, ok bool, err error) {
+	switch kind {
+	case reflect.Int:
+		result, err = convertValue[int](v)
+	case reflect.Int8:
+		result, err = convertValue[int8](v)
+	case reflect.Int16:
+		result, err = convertValue[int16](v)
+	case reflect.Int32:
+		result, err = convertValue[int32](v)
+	case reflect.Int64:
+		result, err = convertValue[int64](v)
+	case reflect.Uint:
+		result, err = convertValue[uint](v)
+	case reflect.Uint8:
+		result, err = convertValue[uint8](v)
+	case reflect.Uint16:
+		result, err = convertValue[uint16](v)
+	case reflect.Uint32:
+		result, err = convertValue[uint32](v)
+	case reflect.Uint64:
+		result, err = convertValue[uint64](v)
+	case reflect.Float32:
+		result, err = convertValue[float32](v)
+	case reflect.Float64:
+		result, err = convertValue[float64](v)
+	case reflect.String:
+		result, err = convertValue[string](v)
+	case reflect.Bool:
+		result, err = convertValue[bool](v)
+	default:
+		return nil, false, nil
+	}
+	return result, true, err
+}
+
+// valueSource 返回脚本值封装的 Go 源对象（生成类、AnyValue）
+func valueSource(v data.Value) (any, bool) {
+	switch val := v.(type) {
+	case data.GetSource:
+		return val.GetSource(), true
+	case *data.ClassValue:
+		if p, ok := val.Class.(data.GetSource); ok {
+			return p.GetSource(), true
+		}
+	case *data.AnyValue:
+		return val.Value, true
+	}
+	return nil, false
+}
+
+// toAny 把脚本值转换为原生 Go 值：数组为 []any，对象为 map[string]any，
+// 生成类与 AnyValue 取其 Go 源对象，null 为 nil
+func toAny(v data.Value) any {
+	switch val := v.(type) {
+	case nil, *data.NullValue:
+		return nil
+	case *data.IntValue:
+		n, _ := val.AsInt()
+		return n
+	case *data.FloatValue:
+		f, _ := val.AsFloat()
+		return f
+	case *data.BoolValue:
+		b, _ := val.AsBool()
+		return b
+	case *data.StringValue:
+		return val.AsString()
+	case *data.ArrayValue:
+		items := make([]any, len(val.Value))
+		for i, item := range val.Value {
+			items[i] = toAny(item)
+		}
+		return items
+	case *data.ObjectValue:
+		props := val.GetProperties()
+		m := make(map[string]any, len(props))
+		for name, prop := range props {
+			m[name] = toAny(prop)
+		}
+		return m
+	}
+	if src, ok := valueSource(v); ok && src != nil {
+		return src
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/php-any/origami/data"
+)
+
+// Status 测试用具名数值类型
+type Status uint8
+
+// convertCase 一条转换用例，convert 在运行时调用 Convert[S]
+type convertCase struct {
+	name    string
+	convert func() (any, error)
+	want    any
+	// 期望错误包含的内容，为空时期望成功
+	err string
+}
+
+// newConvertCase 构造转换为 S 的用例
+func newConvertCase[S any](name string, v data.Value, want any, err string) convertCase {
+	return convertCase{
+		name: name,
+		convert: func() (any, error) {
+			return Convert[S](v)
+		},
+		want: want,
+		err:  err,
+	}
+}
+
+func runConvertCases(t *testing.T, tests []convertCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.convert()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("错误 = %v, 期望包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("结果 = %#v, 期望 %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func object(props map[string]data.Value) *data.ObjectValue {
+	obj := data.NewObjectValue()
+	for name, v := range props {
+		obj.SetProperty(name, v)
+	}
+	return obj
+}
+
+func array(values ...data.Value) data.Value {
+	return data.NewArrayValue(values)
+}
+
+func strs(values ...string) data.Value {
+	items := make([]data.Value, len(values))
+	for i, s := range values {
+		items[i] = data.NewStringValue(s)
+	}
+	return data.NewArrayValue(items)
+}
+
+func TestConvertSlice(t *testing.T) {
+	runConvertCases(t, []convertCase{
+		newConvertCase[[]string]("字符串切片", strs("a", "b"), []string{"a", "b"}, ""),
+		newConvertCase[[][]string]("嵌套切片",
+			array(strs("a"), strs("b", "c")),
+			[][]string{{"a"}, {"b", "c"}}, ""),
+		newConvertCase[[]int]("数值字符串元素",
+			array(data.NewIntValue(1), data.NewStringValue("2")),
+			[]int{1, 2}, ""),
+		newConvertCase[[]*int64]("指针元素与 null",
+			array(data.NewIntValue(1), data.NewNullValue()),
+			[]*int64{ptr(int64(1)), nil}, ""),
+		newConvertCase[[]Status]("具名类型元素",
+			array(data.NewIntValue(1), data.NewStringValue("2")),
+			[]Status{1, 2}, ""),
+		newConvertCase[[][]int]("嵌套元素错误带下标",
+			array(array(data.NewIntValue(1)), array(data.NewStringValue("x"))),
+			nil, "无法转换数组元素 1: 无法转换数组元素 0"),
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// 生成的接口类注册表：脚本类名 -> Go 接口类型
+// 生成类的 GetImplements 据此按方法集判断实现了哪些接口
+var interfaces = struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}{types: make(map[string]reflect.Type)}
+
+// RegisterInterface 注册生成的接口类，由接口类文件的 init 调用
+func RegisterInterface(name string, iface reflect.Type) {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return
+	}
+	interfaces.mu.Lock()
+	defer interfaces.mu.Unlock()
+	interfaces.types[name] = iface
+}
+
+// Implements 返回 t 的方法集满足的已注册接口类名（按名称排序，不含 t 自身）
+func Implements(t reflect.Type) []string {
+	interfaces.mu.RLock()
+	defer interfaces.mu.RUnlock()
+
+	var names []string
+	for name, iface := range interfaces.types {
+		if iface != t && t.Implements(iface) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
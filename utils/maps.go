@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/php-any/origami/data"
+)
+
+// Map 转换模块
+//
+// 脚本对象（{"a": 1}）与数组都可以转换为任意 map[K]V：对象的属性名、数组的下标转换为键，
+// 值按 V 递归转换。反方向由 ValueOf 把 Go 值（含 map[string]any 这类动态值）
+// 封装为原生脚本值，生成代码返回 map 时使用 NewObjectValueFrom 等封装函数。
+
+// convertFromObjectValue 从 ObjectValue 转换，目标为 map 或 any
+func convertFromObjectValue[S any](val *data.ObjectValue) (S, error) {
+	var result S
+	targetType := reflect.TypeOf((*S)(nil)).Elem()
+
+	switch targetType.Kind() {
+	case reflect.Map:
+		m, err := convertToMap(val, targetType)
+		if err != nil {
+			return result, err
+		}
+		return m.Interface().(S), nil
+	case reflect.Interface:
+		if converted, ok := toAny(val).(S); ok {
+			return converted, nil
+		}
+	}
+	return result, fmt.Errorf("无法将对象转换为 %T", result)
+}
+
+// convertToMap 把脚本对象或数组转换为 map 类型 t
+// 对象以属性名为键，数组以下标为键
+func convertToMap(v data.Value, t reflect.Type) (reflect.Value, error) {
+	m := reflect.MakeMap(t)
+	switch val := v.(type) {
+	case *data.ObjectValue:
+		props := val.GetProperties()
+		// 按属性名排序，出错时报告的键稳定
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			if err := setMapEntry(m, name, props[name]); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+	case *data.ArrayValue:
+		for i, item := range val.Value {
+			if err := setMapEntry(m, strconv.Itoa(i), item); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("无法将 %T 转换为 %s", v, t)
+	}
+	return m, nil
+}
+
+// setMapEntry 转换键与值后写入 map
+func setMapEntry(m reflect.Value, name string, item data.Value) error {
+	key, err := convertMapKey(name, m.Type().Key())
+	if err != nil {
+		return fmt.Errorf("转换 map 键 %q 失败: %w", name, err)
+	}
+	elem, err := convertTo(item, m.Type().Elem())
+	if err != nil {
+		return fmt.Errorf("转换 map 键 %q 的值失败: %w", name, err)
+	}
+	m.SetMapIndex(key, elem)
+	return nil
+}
+
+// convertMapKey 把属性名或下标字符串转换为键类型 t（支持字符串、整数、浮点与布尔及其具名类型）
+func convertMapKey(name string, t reflect.Type) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, t.Bits())
+		if err != nil {
+			return key, err
+		}
+		key.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, t.Bits())
+		if err != nil {
+			return key, err
+		}
+		key.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(name, t.Bits())
+		if err != nil {
+			return key, err
+		}
+		key.SetFloat(f)
+	case reflect.Bool:
+		b, err := parseBool(name)
+		if err != nil {
+			return key, err
+		}
+		key.SetBool(b)
+	case reflect.Interface:
+		if !reflect.TypeOf(name).Implements(t) {
+			return key, fmt.Errorf("不支持的键类型 %s", t)
+		}
+		key.Set(reflect.ValueOf(name))
+	default:
+		return key, fmt.Errorf("不支持的键类型 %s", t)
+	}
+	return key, nil
+}
+
+// ValueOf 按 Go 值的动态类型封装为脚本值，用于 any 等静态类型未知的值：
+// 整数/浮点/字符串/布尔转为对应脚本值（超出 int 范围的无符号整数转为浮点），切片与数组转为脚本数组，map 转为脚本对象（键格式化为字符串），
+// nil 转为 null，其余封装为 AnyValue
+func ValueOf(v any) data.Value {
+	if v == nil {
+		return data.NewNullValue()
+	}
+	if dv, ok := v.(data.Value); ok {
+		return dv
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return data.NewIntValue(int(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// 超出 int 范围的无符号整数与 PHP 一致转为浮点，避免回绕为负数
+		return NewUintValue(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return data.NewFloatValue(rv.Float())
+	case reflect.String:
+		return data.NewStringValue(rv.String())
+	case reflect.Bool:
+		return data.NewBoolValue(rv.Bool())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return data.NewNullValue()
+		}
+		values := make([]data.Value, rv.Len())
+		for i := range values {
+			values[i] = ValueOf(rv.Index(i).Interface())
+		}
+		return data.NewArrayValue(values)
+	case reflect.Map:
+		if rv.IsNil() {
+			return data.NewNullValue()
+		}
+		obj := data.NewObjectValue()
+		iter := rv.MapRange()
+		for iter.Next() {
+			obj.SetProperty(fmt.Sprint(iter.Key().Interface()), ValueOf(iter.Value().Interface()))
+		}
+		return obj
+	case reflect.Ptr, reflect.Interface, reflect.Func, reflect.Chan:
+		if rv.IsNil() {
+			return data.NewNullValue()
+		}
+	}
+	return data.NewAnyValue(v)
+}
+
+// NewObjectValueFromMap 将非 string 键的 map 封装为脚本对象，键格式化为字符串，与 ValueOf 的表示一致
+func NewObjectValueFromMap[K comparable, V any](m map[K]V, wrap func(V) data.Value) data.Value {
+	obj := data.NewObjectValue()
+	for k, v := range m {
+		obj.SetProperty(fmt.Sprint(k), wrap(v))
+	}
+	return obj
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/php-any/origami/data"
+)
+
+func TestConvertMap(t *testing.T) {
+	runConvertCases(t, []convertCase{
+		newConvertCase[map[string]int]("对象转字符串键",
+			object(map[string]data.Value{"a": data.NewIntValue(1), "b": data.NewStringValue("2")}),
+			map[string]int{"a": 1, "b": 2}, ""),
+		newConvertCase[map[int]string]("数组下标转整数键",
+			strs("x", "y"),
+			map[int]string{0: "x", 1: "y"}, ""),
+		newConvertCase[map[int]string]("对象属性名转整数键",
+			object(map[string]data.Value{"10": data.NewStringValue("x")}),
+			map[int]string{10: "x"}, ""),
+		newConvertCase[map[string][]string]("嵌套切片值",
+			object(map[string]data.Value{"tags": strs("a", "b")}),
+			map[string][]string{"tags": {"a", "b"}}, ""),
+		newConvertCase[map[int]string]("键无法转换",
+			object(map[string]data.Value{"a": data.NewStringValue("x")}),
+			nil, `转换 map 键 "a" 失败`),
+		newConvertCase[map[string]int]("值无法转换",
+			object(map[string]data.Value{"a": data.NewStringValue("x")}),
+			nil, `转换 map 键 "a" 的值失败`),
+		newConvertCase[map[string]any]("对象转 any 值",
+			object(map[string]data.Value{"a": data.NewIntValue(1), "b": strs("x")}),
+			map[string]any{"a": 1, "b": []any{"x"}}, ""),
+	})
+}
+
+func TestValueOf(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want data.Value
+	}{
+		{"nil", nil, data.NewNullValue()},
+		{"整数", int8(-1), data.NewIntValue(-1)},
+		{"无符号整数", uint32(math.MaxUint32), data.NewIntValue(math.MaxUint32)},
+		{"超出 int 的无符号整数", uint64(math.MaxUint64), data.NewFloatValue(math.MaxUint64)},
+		{"字符串切片", []string{"a"}, strs("a")},
+		{"nil 切片", []int(nil), data.NewNullValue()},
+		{"nil map", map[string]int(nil), data.NewNullValue()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValueOf(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValueOf(%#v) = %#v, 期望 %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewObjectValueFromMap(t *testing.T) {
+	m := map[int64]string{1: "a", -2: "b"}
+	got := NewObjectValueFromMap(m, func(v string) data.Value { return data.NewStringValue(v) })
+	if want := ValueOf(m); !reflect.DeepEqual(mapOf(t, got), mapOf(t, want)) {
+		t.Errorf("NewObjectValueFromMap = %v, 期望与 ValueOf 一致 %v", mapOf(t, got), mapOf(t, want))
+	}
+	// 键在转换回 Go map 时可以还原
+	back, err := Convert[map[int64]string](got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, m) {
+		t.Errorf("Convert = %v, 期望 %v", back, m)
+	}
+}
+
+func TestNewUintValue(t *testing.T) {
+	if got, want := NewUintValue(uint(7)), data.NewIntValue(7); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewUintValue(7) = %#v, 期望 %#v", got, want)
+	}
+	if got, want := NewUintValue(uint64(math.MaxUint64)), ValueOf(uint64(math.MaxUint64)); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewUintValue(MaxUint64) = %#v, 期望 %#v", got, want)
+	}
+}
+
+// mapOf 把脚本对象转换为 Go map 便于比较
+func mapOf(t *testing.T, v data.Value) map[string]any {
+	t.Helper()
+	m, err := Convert[map[string]any](v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// 字符串到数值的解析模块
+//
+// 脚本经常把 HTTP 参数等数值字符串直接传给 Go 的数值参数，转换时按目标类型的宽度解析：
+// 整数只接受十进制（"010" 为 10，不按八进制），超出目标类型范围时报错而不是截断；
+// 首尾空白会被忽略。调用 SetStrictNumericStrings(true) 开启严格模式后不再自动解析；
+// 以 Config.StrictNumericStrings（命令行 -strict-numeric）生成的绑定会在 Load 时开启。
+
+// strictNumericStrings 严格模式开关，默认关闭
+var strictNumericStrings atomic.Bool
+
+// SetStrictNumericStrings 开启或关闭严格模式：开启后字符串不再转换为数值类型，转换返回错误
+func SetStrictNumericStrings(strict bool) {
+	strictNumericStrings.Store(strict)
+}
+
+// StrictNumericStrings 返回是否处于严格模式
+func StrictNumericStrings() bool {
+	return strictNumericStrings.Load()
+}
+
+// parseNumber 把字符串解析为数值类型 S（各宽度的 int/uint/float 及其具名类型）
+func parseNumber[S any](s string) (S, error) {
+	var result S
+	if StrictNumericStrings() {
+		return result, fmt.Errorf("严格模式下不允许将字符串 '%s' 转换为 %T", s, result)
+	}
+
+	rv := reflect.ValueOf(&result).Elem()
+	str := strings.TrimSpace(s)
+	// Bits 只能在数值类型上调用，须在分支内取宽度
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseInt(str, rv.Type().Bits())
+		if err != nil {
+			return result, numberError(s, result, err)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseUint(str, rv.Type().Bits())
+		if err != nil {
+			return result, numberError(s, result, err)
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := parseFloat(str, rv.Type().Bits())
+		if err != nil {
+			return result, numberError(s, result, err)
+		}
+		rv.SetFloat(f)
+	default:
+		return result, fmt.Errorf("无法将字符串 '%s' 转换为非数值类型 %T", s, result)
+	}
+	return result, nil
+}
+
+// numberError 区分超出范围与格式错误
+func numberError(s string, target any, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("字符串 '%s' 超出 %T 的取值范围", s, target)
+	}
+	return fmt.Errorf("无法将字符串 '%s' 解析为 %T", s, target)
+}
+
+// parseInt 按十进制解析有符号整数，bits 为目标宽度
+func parseInt(s string, bits int) (int64, error) {
+	return strconv.ParseInt(s, 10, bits)
+}
+
+// parseUint 按十进制解析无符号整数，bits 为目标宽度（"-1" 视为格式错误）
+func parseUint(s string, bits int) (uint64, error) {
+	return strconv.ParseUint(s, 10, bits)
+}
+
+// parseFloat 解析浮点数，bits 为 32 时超出 float32 范围报错
+func parseFloat(s string, bits int) (float64, error) {
+	return strconv.ParseFloat(s, bits)
+}
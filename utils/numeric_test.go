@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/php-any/origami/data"
+)
+
+func TestConvertNumericString(t *testing.T) {
+	str := data.NewStringValue
+	runConvertCases(t, []convertCase{
+		newConvertCase[int]("十进制整数", str(" 010 "), 10, ""),
+		newConvertCase[int8]("int8 上限", str("127"), int8(127), ""),
+		newConvertCase[int8]("int8 溢出", str("128"), nil, "字符串 '128' 超出 int8 的取值范围"),
+		newConvertCase[int64]("int64 溢出", str("99999999999999999999"), nil, "超出 int64 的取值范围"),
+		newConvertCase[uint]("无符号负数", str("-1"), nil, "无法将字符串 '-1' 解析为 uint"),
+		newConvertCase[Status]("具名类型溢出", str("300"), nil, "字符串 '300' 超出 uint8 的取值范围"),
+		newConvertCase[float64]("浮点", str("1.5"), 1.5, ""),
+		newConvertCase[float32]("float32 溢出", str("1e40"), nil, "超出 float32 的取值范围"),
+		newConvertCase[int]("格式错误", str("abc"), nil, "无法将字符串 'abc' 解析为 int"),
+		newConvertCase[time.Duration]("Duration 按纳秒", str("5"), 5*time.Nanosecond, ""),
+	})
+}
+
+func TestConvertNumericStringStrict(t *testing.T) {
+	SetStrictNumericStrings(true)
+	defer SetStrictNumericStrings(false)
+
+	str := data.NewStringValue
+	runConvertCases(t, []convertCase{
+		newConvertCase[int]("严格模式拒绝整数", str("1"), nil, "严格模式下不允许将字符串 '1' 转换为 int"),
+		newConvertCase[[]float64]("严格模式拒绝嵌套元素", strs("1.5"), nil, "严格模式下不允许"),
+		newConvertCase[int]("严格模式仍接受整数值", data.NewIntValue(1), 1, ""),
+	})
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"reflect"
+
+	"github.com/php-any/origami/data"
+)
+
+// Property 生成类的属性，每次读取都从 Go 源对象取当前值
+//
+// node.ClassProperty 会把首次读取的默认值缓存到对象上，之后不再反映源对象的修改，
+// 这里不缓存，读取时调用 getter。
+type Property struct {
+	Name   string
+	getter func(ctx data.Context) data.Value
+}
+
+// NewProperty 创建实时读取的公开属性
+func NewProperty(name string, getter func(ctx data.Context) data.Value) data.Property {
+	return &Property{Name: name, getter: getter}
+}
+
+func (p *Property) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	if p.getter == nil {
+		return data.NewNullValue(), nil
+	}
+	return p.getter(ctx), nil
+}
+
+func (p *Property) GetName() string                { return p.Name }
+func (p *Property) GetModifier() data.Modifier     { return data.ModifierPublic }
+func (p *Property) GetIsStatic() bool              { return false }
+func (p *Property) GetDefaultValue() data.GetValue { return p }
+
+// NewClassValueOf 将 Go 值封装为生成类的类值，nil 指针或 nil 接口返回 null
+func NewClassValueOf[T any](source T, from func(T) data.ClassStmt, ctx data.Context) data.Value {
+	if isNil(source) {
+		return data.NewNullValue()
+	}
+	return data.NewClassValue(from(source), ctx)
+}
+
+// isNil 判断值是否为 nil（含装有 nil 指针的接口）
+func isNil(v any) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"slices"
+
+	"github.com/php-any/origami/data"
+)
+
+// ClassType 生成类的参数类型
+//
+// data.Class 只接受 *data.ClassValue，而脚本中 new 出的生成类是 *data.ProxyValue，
+// 这里两者都接受。接口类型按 GetImplements（生成类由 Implements 按方法集匹配）判断实现关系，
+// 未实现接口的类值不匹配，调用时由脚本抛出类型错误。
+type ClassType struct {
+	Name      string
+	Interface bool
+}
+
+// NewClassType 创建按类名匹配的类型
+func NewClassType(name string) data.Types {
+	return ClassType{Name: name}
+}
+
+// NewInterfaceType 创建接口类型，接受接口类自身及实现了该接口的类值
+func NewInterfaceType(name string) data.Types {
+	return ClassType{Name: name, Interface: true}
+}
+
+func (c ClassType) Is(value data.Value) bool {
+	var class data.ClassStmt
+	switch v := value.(type) {
+	case *data.ClassValue:
+		class = v.Class
+	case *data.ProxyValue:
+		class = v.Class
+	default:
+		return false
+	}
+	if class.GetName() == c.Name {
+		return true
+	}
+	return c.Interface && slices.Contains(class.GetImplements(), c.Name)
+}
+
+func (c ClassType) String() string {
+	return c.Name
+}
+
+// MapType map 参数的类型：脚本对象与数组都可以转换为 map（属性名或下标作为键）
+type MapType struct{}
+
+func (MapType) Is(value data.Value) bool {
+	switch value.(type) {
+	case *data.ObjectValue, *data.ArrayValue:
+		return true
+	}
+	return false
+}
+
+func (MapType) String() string {
+	return "object|array"
+}
+
+// IntType 整数参数的类型：接受整数值；非严格模式下也接受字符串，转换时按目标宽度解析（见 SetStrictNumericStrings）
+type IntType struct{}
+
+func (IntType) Is(value data.Value) bool {
+	switch value.(type) {
+	case *data.IntValue:
+		return true
+	case *data.StringValue:
+		return !StrictNumericStrings()
+	}
+	return false
+}
+
+func (IntType) String() string {
+	return "int"
+}
+
+// FloatType 浮点参数的类型：接受整数与浮点值；非严格模式下也接受字符串
+type FloatType struct{}
+
+func (FloatType) Is(value data.Value) bool {
+	switch value.(type) {
+	case *data.StringValue:
+		return !StrictNumericStrings()
+	case data.AsFloat:
+		return true
+	}
+	return false
+}
+
+func (FloatType) String() string {
+	return "float"
+}
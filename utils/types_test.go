@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/php-any/origami/data"
+)
+
+// fakeClass 测试用类，只实现类名与接口列表
+type fakeClass struct {
+	data.ClassStmt
+	name       string
+	implements []string
+}
+
+func (c *fakeClass) GetName() string         { return c.name }
+func (c *fakeClass) GetImplements() []string { return c.implements }
+
+func TestInterfaceTypeIs(t *testing.T) {
+	iface := NewInterfaceType("demo\\Describer")
+	tests := []struct {
+		name  string
+		value data.Value
+		want  bool
+	}{
+		{"接口类自身", data.NewProxyValue(&fakeClass{name: "demo\\Describer"}, nil), true},
+		{"实现了接口的类", data.NewProxyValue(&fakeClass{name: "demo\\AdminUser", implements: []string{"demo\\Describer"}}, nil), true},
+		{"实现了接口的脚本类", data.NewClassValue(&fakeClass{name: "App\\Item", implements: []string{"demo\\Describer"}}, nil), true},
+		{"未实现接口的类", data.NewProxyValue(&fakeClass{name: "demo\\User", implements: []string{"demo\\UserService"}}, nil), false},
+		{"非类值", data.NewStringValue("demo\\Describer"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := iface.Is(tt.value); got != tt.want {
+				t.Errorf("Is() = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+
+	// 类类型只按类名匹配，不因实现关系放宽
+	user := NewClassType("demo\\User")
+	if user.Is(data.NewProxyValue(&fakeClass{name: "demo\\AdminUser", implements: []string{"demo\\User"}}, nil)) {
+		t.Error("类类型不应接受其他类")
+	}
+}
@@ -3,7 +3,6 @@ package utils
 import (
 	"fmt"
 	"reflect"
-	"time"
 
 	"github.com/php-any/origami/data"
 )
@@ -17,12 +16,16 @@ func ConvertFromIndex[S any](ctx data.Context, index int) (S, error) {
 	}
 
 	// 直接类型转换
-	if converted, err := convertValue[S](v); err == nil {
+	converted, err := convertValue[S](v)
+	if err == nil {
 		return converted, nil
 	}
 
-	// 类型别名特殊处理
-	return convertTypeAlias[S](v)
+	// 类型别名特殊处理；仍失败时返回直接转换的错误（含失败的数组下标、map 键等细节）
+	if aliased, aliasErr := convertTypeAlias[S](v); aliasErr == nil {
+		return aliased, nil
+	}
+	return result, err
 }
 
 // convertValue 通用值转换
@@ -31,7 +34,7 @@ func convertValue[S any](v data.Value) (S, error) {
 	switch val := v.(type) {
 	case data.GetSource:
 		if src := val.GetSource(); src != nil {
-			if converted, ok := src.(S); ok {
+			if converted, ok := sourceAs[S](src); ok {
 				return converted, nil
 			}
 		}
@@ -40,7 +43,7 @@ func convertValue[S any](v data.Value) (S, error) {
 	case *data.ClassValue:
 		if p, ok := val.Class.(data.GetSource); ok {
 			if src := p.GetSource(); src != nil {
-				if converted, ok := src.(S); ok {
+				if converted, ok := sourceAs[S](src); ok {
 					return converted, nil
 				}
 			}
@@ -68,11 +71,30 @@ func convertValue[S any](v data.Value) (S, error) {
 	case *data.ArrayValue:
 		return convertFromArrayValue[S](val)
 
+	case *data.ObjectValue:
+		return convertFromObjectValue[S](val)
+
 	default:
 		return result, fmt.Errorf("不支持的值类型: %T", v)
 	}
 }
 
+// sourceAs 将封装的 Go 源对象断言为 S
+// 双向 channel 可以传给只读或只写的 channel 参数（chan T -> <-chan T / chan<- T）
+func sourceAs[S any](src any) (S, bool) {
+	if converted, ok := src.(S); ok {
+		return converted, true
+	}
+	var result S
+	target := reflect.TypeOf((*S)(nil)).Elem()
+	rv := reflect.ValueOf(src)
+	if target.Kind() == reflect.Chan && rv.Kind() == reflect.Chan &&
+		rv.Type().ChanDir() == reflect.BothDir && rv.Type().Elem() == target.Elem() {
+		return rv.Convert(target).Interface().(S), true
+	}
+	return result, false
+}
+
 // convertFromIntValue 从 IntValue 转换
 func convertFromIntValue[S any](val *data.IntValue) (S, error) {
 	var result S
@@ -140,7 +162,8 @@ func convertFromStringValue[S any](val *data.StringValue) (S, error) {
 		}
 		return result, fmt.Errorf("无法将字符串 '%s' 转换为布尔类型", strVal)
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
-		return result, fmt.Errorf("字符串到数值转换需要两步转换，请使用模板生成的两步转换")
+		// 数值字符串按目标宽度解析，严格模式下报错
+		return parseNumber[S](strVal)
 	}
 
 	// 如果直接类型断言失败，使用反射处理复杂类型
@@ -293,167 +316,168 @@ func convertFromArrayValue[S any](val *data.ArrayValue) (S, error) {
 	switch any(result).(type) {
 	case []int:
 		slice := make([]int, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[int](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []int8:
 		slice := make([]int8, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[int8](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []int16:
 		slice := make([]int16, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[int16](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []int32:
 		slice := make([]int32, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[int32](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []int64:
 		slice := make([]int64, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[int64](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []uint:
 		slice := make([]uint, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[uint](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []uint8:
 		slice := make([]uint8, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[uint8](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []uint16:
 		slice := make([]uint16, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[uint16](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []uint32:
 		slice := make([]uint32, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[uint32](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []uint64:
 		slice := make([]uint64, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if intVal, err := convertValue[uint64](item); err == nil {
 				slice = append(slice, intVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []float32:
 		slice := make([]float32, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if floatVal, err := convertValue[float32](item); err == nil {
 				slice = append(slice, floatVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []float64:
 		slice := make([]float64, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if floatVal, err := convertValue[float64](item); err == nil {
 				slice = append(slice, floatVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []string:
 		slice := make([]string, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if strVal, err := convertValue[string](item); err == nil {
 				slice = append(slice, strVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	case []bool:
 		slice := make([]bool, 0, len(val.Value))
-		for _, item := range val.Value {
+		for i, item := range val.Value {
 			if boolVal, err := convertValue[bool](item); err == nil {
 				slice = append(slice, boolVal)
 			} else {
-				return result, fmt.Errorf("转换数组元素失败: %w", err)
+				return result, fmt.Errorf("无法转换数组元素 %d: %w", i, err)
 			}
 		}
 		return any(slice).(S), nil
 	}
 
-	// 如果直接类型断言失败，使用反射处理复杂类型
+	// 其余类型按目标元素类型递归转换：生成类取源对象、嵌套数组逐层转换、具名类型按底层类型转换
 	targetType := reflect.TypeOf((*S)(nil)).Elem()
-
-	// 只支持切片类型
-	if targetType.Kind() != reflect.Slice {
-		return result, fmt.Errorf("无法将数组转换为非切片类型 %T", result)
-	}
-
-	// 创建目标切片
-	slice := reflect.MakeSlice(targetType, len(val.Value), len(val.Value))
-
-	for i, item := range val.Value {
-		// 递归转换每个元素
-		if converted, err := convertValue[any](item); err == nil {
-			slice.Index(i).Set(reflect.ValueOf(converted))
-		} else {
-			return result, fmt.Errorf("无法转换数组元素 %d: %v", i, err)
+	switch targetType.Kind() {
+	case reflect.Map:
+		// 数组按下标转换为 map
+		m, err := convertToMap(val, targetType)
+		if err != nil {
+			return result, err
+		}
+		return m.Interface().(S), nil
+	case reflect.Slice, reflect.Array:
+		converted, err := convertToSlice(val, targetType)
+		if err != nil {
+			return result, err
+		}
+		return converted.Interface().(S), nil
+	case reflect.Interface:
+		if converted, ok := toAny(val).(S); ok {
+			return converted, nil
 		}
 	}
-
-	return slice.Interface().(S), nil
+	return result, fmt.Errorf("无法将数组转换为非切片类型 %T", result)
 }
 
 // convertTypeAlias 处理类型别名转换
@@ -465,53 +489,30 @@ func convertTypeAlias[S any](v data.Value) (S, error) {
 		return s, nil
 	}
 
-	// 特殊处理 time.Duration
-	if _, ok := any(result).(time.Duration); ok {
-		if intVal, ok := v.(data.AsInt); ok {
-			if duration, err := intVal.AsInt(); err == nil {
-				return any(time.Duration(duration)).(S), nil
-			}
-		}
-		return result, fmt.Errorf("无法将 %T 转换为 time.Duration", v)
-	}
-
 	// 如果直接类型断言失败，使用反射处理复杂类型
 	targetType := reflect.TypeOf((*S)(nil)).Elem()
 
 	// 检查是否为具名类型
 	if targetType.PkgPath() != "" && targetType.Name() != "" {
-		// 特殊处理 time.Duration
-		if targetType.PkgPath() == "time" && targetType.Name() == "Duration" {
-			if intVal, ok := v.(data.AsInt); ok {
-				if duration, err := intVal.AsInt(); err == nil {
-					return any(time.Duration(duration)).(S), nil
-				}
-			}
-			return result, fmt.Errorf("无法将 %T 转换为 time.Duration", v)
-		}
-
 		// 其他具名类型尝试直接转换
 		if converted, ok := v.(S); ok {
 			return converted, nil
 		}
+
+		// 底层为基础类型的具名类型（time.Duration、type Status string 等）按底层类型转换，
+		// 数值字符串的解析与严格模式与基础类型一致
+		if scalar, ok, err := convertScalar(v, targetType.Kind()); ok {
+			if err != nil {
+				return result, err
+			}
+			return reflect.ValueOf(scalar).Convert(targetType).Interface().(S), nil
+		}
 	}
 
 	return result, fmt.Errorf("无法转换类型 %T 到 %T", v, result)
 }
 
 // 辅助函数
-func parseInt(s string) (int, error) {
-	var result int
-	_, err := fmt.Sscanf(s, "%d", &result)
-	return result, err
-}
-
-func parseFloat(s string) (float64, error) {
-	var result float64
-	_, err := fmt.Sscanf(s, "%g", &result)
-	return result, err
-}
-
 func parseBool(s string) (bool, error) {
 	switch s {
 	case "true", "1", "yes", "on":
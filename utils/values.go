@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"math"
+
+	"github.com/php-any/origami/data"
+)
+
+// NewArrayValueFrom 将切片逐个元素封装为脚本数组
+// wrap 由生成代码按元素类型提供（如 data.NewStringValue、生成类的 NewXxxClassFrom）
+func NewArrayValueFrom[T any](items []T, wrap func(T) data.Value) data.Value {
+	values := make([]data.Value, 0, len(items))
+	for _, item := range items {
+		values = append(values, wrap(item))
+	}
+	return data.NewArrayValue(values)
+}
+
+// NewObjectValueFrom 将 string 键的 map 封装为脚本对象
+func NewObjectValueFrom[K ~string, V any](m map[K]V, wrap func(V) data.Value) data.Value {
+	obj := data.NewObjectValue()
+	for k, v := range m {
+		obj.SetProperty(string(k), wrap(v))
+	}
+	return obj
+}
+
+// NewUintValue 封装 uint、uint64 等可能超出 int 范围的无符号整数，与 ValueOf 一致：超出 int 范围时转为浮点
+func NewUintValue[T ~uint | ~uint64](v T) data.Value {
+	if uint64(v) > math.MaxInt {
+		return data.NewFloatValue(float64(v))
+	}
+	return data.NewIntValue(int(v))
+}